@@ -294,16 +294,19 @@ func Stacked(err error) error {
 	}
 }
 
-// WithOptions new error with some option func
+// WithOptions new error with some option func.
+// the options will also be used on render the error stack.
 func WithOptions(msg string, fns ...func(opt *ErrStackOpt)) error {
 	opt := newErrOpt()
 	for _, fn := range fns {
 		fn(opt)
 	}
 
+	st := callersStack(opt.SkipDepth, opt.TraceDepth)
+	st.opt = opt
 	return &ErrorX{
 		msg:   msg,
-		stack: callersStack(opt.SkipDepth, opt.TraceDepth),
+		stack: st,
 	}
 }
 
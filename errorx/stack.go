@@ -1,16 +1,25 @@
 package errorx
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"go/build"
 	"io"
+	"os"
 	"path/filepath"
 	"runtime"
+	"runtime/debug"
 	"strconv"
+	"strings"
 )
 
 // stack represents a stack of program counters.
-type stack []uintptr
+type stack struct {
+	pcs []uintptr
+	// opt for render the stack. if is nil, will use stdOpt
+	opt *ErrStackOpt
+}
 
 // Format stack trace
 func (s *stack) Format(fs fmt.State, verb rune) {
@@ -23,17 +32,24 @@ func (s *stack) Format(fs fmt.State, verb rune) {
 
 // StackLen for error
 func (s *stack) StackLen() int {
-	return len(*s)
+	return len(s.pcs)
 }
 
 // WriteTo for error
 func (s *stack) WriteTo(w io.Writer) (int64, error) {
-	if len(*s) == 0 {
+	if len(s.pcs) == 0 {
 		return 0, nil
 	}
 
+	opt := s.opt
+	if opt == nil {
+		opt = stdOpt
+	}
+
+	var stdNum int
+
 	nn, _ := w.Write([]byte("\nSTACK:\n"))
-	for _, pc := range *s {
+	for i, pc := range s.pcs {
 		// For historical reasons if pc is interpreted as a uintptr
 		// its value represents the program counter + 1.
 		fc := runtime.FuncForPC(pc - 1)
@@ -41,15 +57,41 @@ func (s *stack) WriteTo(w io.Writer) (int64, error) {
 			continue
 		}
 
+		// f.Name() eg: github.com/gookit/goutil/errorx_test.TestWithPrev()
+		name := fc.Name()
+		if !opt.allowFrame(name) {
+			continue
+		}
+
 		// file eg: workspace/godev/gookit/goutil/errorx/errorx_test.go
 		file, line := fc.FileLine(pc - 1)
-		// f.Name() eg: github.com/gookit/goutil/errorx_test.TestWithPrev()
-		location := fc.Name() + "()\n  " + file + ":" + strconv.Itoa(line) + "\n"
+		if opt.CollapseStd && isStdFunc(name, file) {
+			stdNum++
+			continue
+		}
+
+		if stdNum > 0 {
+			n, _ := fmt.Fprintf(w, "  ... %d std frames omitted\n", stdNum)
+			nn += n
+			stdNum = 0
+		}
+
+		location := name + "()\n  " + opt.trimPath(file) + ":" + strconv.Itoa(line) + "\n"
 
 		n, _ := w.Write([]byte(location))
 		nn += n
+
+		// render source context for the top frame(where the error is raised)
+		if i == 0 && opt.SourceLines > 0 {
+			n, _ = w.Write(sourceSnippet(file, line, opt.SourceLines))
+			nn += n
+		}
 	}
 
+	if stdNum > 0 {
+		n, _ := fmt.Fprintf(w, "  ... %d std frames omitted\n", stdNum)
+		nn += n
+	}
 	return int64(nn), nil
 }
 
@@ -62,18 +104,18 @@ func (s *stack) String() string {
 
 // StackFrames stack frame list
 func (s *stack) StackFrames() *runtime.Frames {
-	return runtime.CallersFrames(*s)
+	return runtime.CallersFrames(s.pcs)
 }
 
 // CallerPC the caller PC value in the stack. it is first frame.
 func (s *stack) CallerPC() uintptr {
-	if len(*s) == 0 {
+	if len(s.pcs) == 0 {
 		return 0
 	}
 
 	// For historical reasons if pc is interpreted as a uintptr
 	// its value represents the program counter + 1.
-	return (*s)[0] - 1
+	return s.pcs[0] - 1
 }
 
 /*************************************************************
@@ -140,6 +182,40 @@ func (f *Func) MarshalText() ([]byte, error) {
 type ErrStackOpt struct {
 	SkipDepth  int
 	TraceDepth int
+
+	// OnlyPkgs only render the frames whose func name has one of the prefixes.
+	//
+	// eg: "github.com/gookit/goutil"
+	OnlyPkgs []string
+	// SkipPkgs skip render the frames whose func name has one of the prefixes.
+	//
+	// eg: "runtime.", "github.com/some/vendor"
+	SkipPkgs []string
+	// CollapseStd collapse continuous std library frames to one line.
+	CollapseStd bool
+	// TrimPrefixes trim the prefixes from the frame file path.
+	TrimPrefixes []string
+	// SourceLines render N lines of source context around the top frame.
+	// only render on the source file exists locally.
+	SourceLines int
+}
+
+// allowFrame check the frame func name is allowed by OnlyPkgs and SkipPkgs
+func (o *ErrStackOpt) allowFrame(name string) bool {
+	if len(o.OnlyPkgs) > 0 && !hasAnyPrefix(name, o.OnlyPkgs) {
+		return false
+	}
+	return !hasAnyPrefix(name, o.SkipPkgs)
+}
+
+// trimPath trim the file path by TrimPrefixes
+func (o *ErrStackOpt) trimPath(file string) string {
+	for _, prefix := range o.TrimPrefixes {
+		if prefix != "" && strings.HasPrefix(file, prefix) {
+			return strings.TrimLeft(file[len(prefix):], "/")
+		}
+	}
+	return file
 }
 
 // default option
@@ -178,10 +254,150 @@ func TraceDepth(traceDepth int) func(opt *ErrStackOpt) {
 	}
 }
 
+// OnlyPkgs setting. only render frames of the package prefixes
+func OnlyPkgs(prefixes ...string) func(opt *ErrStackOpt) {
+	return func(opt *ErrStackOpt) {
+		opt.OnlyPkgs = append(opt.OnlyPkgs, prefixes...)
+	}
+}
+
+// SkipPkgs setting. skip render frames of the package prefixes
+func SkipPkgs(prefixes ...string) func(opt *ErrStackOpt) {
+	return func(opt *ErrStackOpt) {
+		opt.SkipPkgs = append(opt.SkipPkgs, prefixes...)
+	}
+}
+
+// CollapseStd setting. collapse std library frames
+func CollapseStd(opt *ErrStackOpt) {
+	opt.CollapseStd = true
+}
+
+// TrimPrefixes setting. trim prefixes from the frame file path
+func TrimPrefixes(prefixes ...string) func(opt *ErrStackOpt) {
+	return func(opt *ErrStackOpt) {
+		opt.TrimPrefixes = append(opt.TrimPrefixes, prefixes...)
+	}
+}
+
+// TrimGoPath setting. trim the GOPATH module cache, GOPATH/src, GOROOT/src
+// and current work dir prefixes from the frame file path.
+func TrimGoPath(opt *ErrStackOpt) {
+	var prefixes []string
+	if gp := build.Default.GOPATH; gp != "" {
+		for _, dir := range filepath.SplitList(gp) {
+			dir = filepath.ToSlash(dir)
+			prefixes = append(prefixes, dir+"/pkg/mod/", dir+"/src/")
+		}
+	}
+	if gr := build.Default.GOROOT; gr != "" {
+		prefixes = append(prefixes, filepath.ToSlash(gr)+"/src/")
+	}
+	if wd, err := os.Getwd(); err == nil {
+		prefixes = append(prefixes, filepath.ToSlash(wd)+"/")
+	}
+
+	opt.TrimPrefixes = append(opt.TrimPrefixes, prefixes...)
+}
+
+// SourceLines setting. render N lines of source context around the top frame
+func SourceLines(n int) func(opt *ErrStackOpt) {
+	return func(opt *ErrStackOpt) {
+		opt.SourceLines = n
+	}
+}
+
 func callersStack(skip, depth int) *stack {
 	pcs := make([]uintptr, depth)
 	num := runtime.Callers(skip, pcs[:])
 
-	var st stack = pcs[0:num]
-	return &st
+	return &stack{pcs: pcs[0:num]}
+}
+
+// the main module path. eg: "github.com/gookit/goutil"
+var mainModPath = func() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+}()
+
+// isStdFunc check the func is from std library by func name and source file.
+//
+//   - the std package path first element does not contain dot. eg: "runtime", "net/http"
+//   - the packages in main module are not std. eg: "myapp/internal/x"
+//   - the std source file is under the GOROOT/src, if the file path is absolute.
+func isStdFunc(name, file string) bool {
+	pkgPath := funcPkgPath(name)
+	if pkgPath == "main" {
+		return false
+	}
+
+	first := pkgPath
+	if i := strings.IndexByte(pkgPath, '/'); i > 0 {
+		first = pkgPath[:i]
+	}
+	if strings.Contains(first, ".") {
+		return false
+	}
+
+	if mainModPath != "" && (pkgPath == mainModPath || strings.HasPrefix(pkgPath, mainModPath+"/")) {
+		return false
+	}
+
+	if goRoot := runtime.GOROOT(); goRoot != "" && filepath.IsAbs(file) {
+		return strings.HasPrefix(filepath.ToSlash(file), filepath.ToSlash(goRoot)+"/src/")
+	}
+	return true
+}
+
+// get the package path from func name. eg: "net/http.(*Server).Serve" => "net/http"
+func funcPkgPath(name string) string {
+	lastSlash := strings.LastIndexByte(name, '/')
+	if i := strings.IndexByte(name[lastSlash+1:], '.'); i >= 0 {
+		return name[:lastSlash+1+i]
+	}
+	return name
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceSnippet read source lines around the line number. returns empty on file not exists.
+//
+// Returns eg:
+//
+//	  33 | 	err := errorx.New("error message")
+//	> 34 | 	return err
+//	  35 | }
+func sourceSnippet(file string, line, around int) []byte {
+	fh, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer fh.Close()
+
+	start, end := line-around, line+around
+	width := len(strconv.Itoa(end))
+
+	var buf bytes.Buffer
+	s := bufio.NewScanner(fh)
+	for num := 1; s.Scan() && num <= end; num++ {
+		if num < start {
+			continue
+		}
+
+		mark := "    "
+		if num == line {
+			mark = "  > "
+		}
+		_, _ = fmt.Fprintf(&buf, "%s%*d | %s\n", mark, width, num, s.Text())
+	}
+	return buf.Bytes()
 }
@@ -3,6 +3,7 @@ package errorx
 import (
 	"bytes"
 	"reflect"
+	"runtime"
 	"testing"
 
 	"github.com/gookit/goutil/x/assert"
@@ -48,3 +49,48 @@ func TestStack_Format(t *testing.T) {
 	assert.True(t, st.StackLen() > 0)
 	assert.NotEmpty(t, st.StackFrames())
 }
+
+func TestErrStackOpt_filter(t *testing.T) {
+	err := WithOptions("error message", TraceDepth(20), SkipPkgs("testing."), CollapseStd)
+	str := err.(*ErrorX).StackString()
+	assert.NotContains(t, str, "testing.tRunner")
+	assert.Contains(t, str, "std frames omitted")
+	assert.Contains(t, str, "errorx.TestErrStackOpt_filter()")
+
+	err = WithOptions("error message", OnlyPkgs("testing."))
+	str = err.(*ErrorX).StackString()
+	assert.Contains(t, str, "testing.tRunner")
+	assert.NotContains(t, str, "errorx.TestErrStackOpt_filter()")
+
+	goSrc := runtime.GOROOT() + "/src/"
+	assert.True(t, isStdFunc("runtime.goexit", goSrc+"runtime/asm_amd64.s"))
+	assert.True(t, isStdFunc("net/http.(*Server).Serve", goSrc+"net/http/server.go"))
+	assert.False(t, isStdFunc("main.main", "/path/to/myapp/main.go"))
+	assert.False(t, isStdFunc("github.com/gookit/goutil/errorx.New", "/path/to/errorx/errorx.go"))
+	// the package in module without dot
+	assert.False(t, isStdFunc("myapp/internal/x.Run", "/path/to/myapp/internal/x/x.go"))
+	assert.Eq(t, "net/http", funcPkgPath("net/http.(*Server).Serve"))
+	assert.Eq(t, "myapp/internal/x", funcPkgPath("myapp/internal/x.Run"))
+	assert.Eq(t, "runtime", funcPkgPath("runtime.goexit"))
+}
+
+func TestErrStackOpt_trimAndSource(t *testing.T) {
+	err := WithOptions("error message", TrimGoPath, SourceLines(1))
+	str := err.(*ErrorX).StackString()
+	assert.Contains(t, str, "\n  stack_test.go:")
+	assert.Contains(t, str, ` > `)
+	assert.Contains(t, str, `err := WithOptions("error message", TrimGoPath, SourceLines(1))`)
+
+	// the top frame is skipped, dont render source for other frames
+	err = WithOptions("error message", SkipPkgs("github.com/gookit/goutil/errorx.TestErrStackOpt"), SourceLines(1))
+	str = err.(*ErrorX).StackString()
+	assert.Contains(t, str, "testing.tRunner")
+	assert.NotContains(t, str, ` > `)
+
+	opt := newErrOpt()
+	TrimPrefixes("/path/to")(opt)
+	assert.Eq(t, "some/file.go", opt.trimPath("/path/to/some/file.go"))
+	assert.Eq(t, "/other/file.go", opt.trimPath("/other/file.go"))
+
+	assert.Empty(t, sourceSnippet("not-exist.go", 2, 2))
+}
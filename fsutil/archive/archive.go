@@ -0,0 +1,336 @@
+// Package archive provide some util functions for create and extract zip, tar.gz archives.
+//
+// All extract functions have zip-slip protection, and support limit
+// the file count and uncompressed size against zip bombs.
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+)
+
+// errors for extract archive
+var (
+	// ErrIllegalPath the archive entry path or symlink target is outside the dst dir.
+	ErrIllegalPath = errors.New("archive: illegal file path")
+	// ErrTooLarge the archive exceeds the size or file count limit.
+	ErrTooLarge = errors.New("archive: exceeds the size limit")
+)
+
+// Options for create and extract archive
+type Options struct {
+	// Filters for filter the files. return false will skip it.
+	//
+	//  - on create, the fPath is full path of the src file or dir.
+	//  - on extract, the fPath is the entry name in the archive. eg: "sub/file.txt"
+	Filters []fsutil.FilterFunc
+	// MaxFiles limit the entry count on extract. 0 is not limit.
+	MaxFiles int
+	// MaxFileSize limit the uncompressed size of each file on extract. 0 is not limit.
+	MaxFileSize int64
+	// MaxTotalSize limit the uncompressed total size on extract. 0 is not limit.
+	MaxTotalSize int64
+}
+
+// OptionFunc for create and extract archive
+type OptionFunc func(opt *Options)
+
+// NewOptions create a new Options instance
+func NewOptions(optFns ...OptionFunc) *Options {
+	opt := &Options{}
+	for _, fn := range optFns {
+		fn(opt)
+	}
+	return opt
+}
+
+// WithFilters add filters for create and extract archive. see fsutil.FilterFunc
+func WithFilters(fns ...fsutil.FilterFunc) OptionFunc {
+	return func(opt *Options) {
+		opt.Filters = append(opt.Filters, fns...)
+	}
+}
+
+// WithLimits set the max file count, max each file size and max total size on extract.
+func WithLimits(maxFiles int, maxFileSize, maxTotalSize int64) OptionFunc {
+	return func(opt *Options) {
+		opt.MaxFiles = maxFiles
+		opt.MaxFileSize = maxFileSize
+		opt.MaxTotalSize = maxTotalSize
+	}
+}
+
+// skip check the path should be skipped by filters
+func (o *Options) skip(fPath string, ent fs.DirEntry) bool {
+	return len(o.Filters) > 0 && fsutil.ApplyFilters(fPath, ent, o.Filters)
+}
+
+// srcEntry info for create archive
+type srcEntry struct {
+	// path full path of the src file
+	path string
+	// name entry name in archive, use slash separator. eg: "sub/file.txt"
+	name string
+	info fs.FileInfo
+	// link target for symlink
+	link string
+}
+
+// walkSrc walk the src file or dir, call fn for each entry
+func walkSrc(src string, opt *Options, fn func(se *srcEntry) error) error {
+	fi, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	// single file
+	if !fi.IsDir() {
+		return fn(&srcEntry{path: src, name: filepath.Base(src), info: fi})
+	}
+
+	return filepath.WalkDir(src, func(fPath string, ent fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if fPath == src {
+			return nil
+		}
+
+		if opt.skip(fPath, ent) {
+			if ent.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		info, err := ent.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, fPath)
+		if err != nil {
+			return err
+		}
+
+		se := &srcEntry{path: fPath, name: filepath.ToSlash(rel), info: info}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if se.link, err = os.Readlink(fPath); err != nil {
+				return err
+			}
+		}
+		return fn(se)
+	})
+}
+
+// extractor common logic for extract archive entries
+type extractor struct {
+	opt    *Options
+	dstDir string
+	// files count and total size
+	files int
+	total int64
+	// dirs for set mode and mtime after all files extracted
+	dirs []dirMeta
+}
+
+type dirMeta struct {
+	path  string
+	mode  fs.FileMode
+	mtime time.Time
+}
+
+func newExtractor(dstDir string, opt *Options) (*extractor, error) {
+	dstDir, err := filepath.Abs(dstDir)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(dstDir, fsutil.DefaultDirPerm); err != nil {
+		return nil, err
+	}
+	// use the real path for check the resolved paths
+	if dstDir, err = filepath.EvalSymlinks(dstDir); err != nil {
+		return nil, err
+	}
+	return &extractor{opt: opt, dstDir: dstDir}, nil
+}
+
+// target check the entry name and returns the full dst path.
+// if skip is true, the entry should be skipped by filters.
+func (e *extractor) target(name string, fi fs.FileInfo) (dstPath string, skip bool, err error) {
+	if e.opt.skip(name, fs.FileInfoToDirEntry(fi)) {
+		return "", true, nil
+	}
+
+	e.files++
+	if e.opt.MaxFiles > 0 && e.files > e.opt.MaxFiles {
+		return "", false, fmt.Errorf("%w: more than %d files", ErrTooLarge, e.opt.MaxFiles)
+	}
+
+	dstPath, err = e.safeJoin(name)
+	return dstPath, false, err
+}
+
+// safeJoin join the name to dst dir, returns error on the path is outside the dst dir.
+func (e *extractor) safeJoin(name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return "", fmt.Errorf("%w: %s", ErrIllegalPath, name)
+	}
+
+	dstPath := filepath.Join(e.dstDir, name)
+	if !e.within(dstPath) {
+		return "", fmt.Errorf("%w: %s", ErrIllegalPath, name)
+	}
+	return dstPath, nil
+}
+
+func (e *extractor) within(fPath string) bool {
+	return fPath == e.dstDir || strings.HasPrefix(fPath, e.dstDir+string(filepath.Separator))
+}
+
+// realDir resolve the symlinks in the dir path, returns error on the real path is outside the dst dir.
+// it can prevent write files to outside by the extracted symlinks. eg: "a" -> ".", "a/b" -> ".."
+func (e *extractor) realDir(dir string) (string, error) {
+	// find the nearest exists parent dir, the not exists sub dirs will be created under it.
+	sub := ""
+	for p := dir; e.within(p); p = filepath.Dir(p) {
+		realPath, err := filepath.EvalSymlinks(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				sub = filepath.Join(filepath.Base(p), sub)
+				continue
+			}
+			return "", err
+		}
+
+		if !e.within(realPath) {
+			break
+		}
+		return filepath.Join(realPath, sub), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrIllegalPath, dir)
+}
+
+// removeLink remove the exists symlink on the dst path, avoid write file through it.
+func removeLink(dstPath string) error {
+	if fi, err := os.Lstat(dstPath); err == nil && fi.Mode()&fs.ModeSymlink != 0 {
+		return os.Remove(dstPath)
+	}
+	return nil
+}
+
+func (e *extractor) mkdir(dstPath string, mode fs.FileMode, mtime time.Time) error {
+	if _, err := e.realDir(dstPath); err != nil {
+		return err
+	}
+
+	e.dirs = append(e.dirs, dirMeta{path: dstPath, mode: mode.Perm(), mtime: mtime})
+	return os.MkdirAll(dstPath, fsutil.DefaultDirPerm)
+}
+
+// writeFile write the reader contents to dst file, check the size limits.
+func (e *extractor) writeFile(dstPath string, r io.Reader, mode fs.FileMode, mtime time.Time) error {
+	if _, err := e.realDir(filepath.Dir(dstPath)); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), fsutil.DefaultDirPerm); err != nil {
+		return err
+	}
+	if err := removeLink(dstPath); err != nil {
+		return err
+	}
+
+	// limit < 0 means no limit, keep it apart from the remaining total budget
+	limit := int64(-1)
+	if e.opt.MaxFileSize > 0 {
+		limit = e.opt.MaxFileSize
+	}
+	if e.opt.MaxTotalSize > 0 {
+		remain := e.opt.MaxTotalSize - e.total
+		if remain <= 0 {
+			return fmt.Errorf("%w: total size exceeds %d", ErrTooLarge, e.opt.MaxTotalSize)
+		}
+		if limit < 0 || remain < limit {
+			limit = remain
+		}
+	}
+	if limit >= 0 {
+		// read one more byte for check exceeds limit
+		r = io.LimitReader(r, limit+1)
+	}
+
+	fh, err := os.OpenFile(dstPath, fsutil.FsCWTFlags, 0600)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(fh, r)
+	if err1 := fh.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+
+	e.total += n
+	if limit >= 0 && n > limit {
+		_ = os.Remove(dstPath)
+		return fmt.Errorf("%w: %s", ErrTooLarge, dstPath)
+	}
+
+	if err = os.Chmod(dstPath, mode.Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(dstPath, mtime, mtime)
+}
+
+// symlink create symlink, the link target must be inside the dst dir.
+func (e *extractor) symlink(dstPath, target string) error {
+	if filepath.IsAbs(target) || !e.within(filepath.Join(filepath.Dir(dstPath), target)) {
+		return fmt.Errorf("%w: symlink %s -> %s", ErrIllegalPath, dstPath, target)
+	}
+
+	// check the target by the real parent dir. the parent may be an extracted symlink.
+	realDir, err := e.realDir(filepath.Dir(dstPath))
+	if err != nil {
+		return err
+	}
+	if !e.within(filepath.Join(realDir, target)) {
+		return fmt.Errorf("%w: symlink %s -> %s", ErrIllegalPath, dstPath, target)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dstPath), fsutil.DefaultDirPerm); err != nil {
+		return err
+	}
+	if _, err = os.Lstat(dstPath); err == nil {
+		if err = os.Remove(dstPath); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(target, dstPath)
+}
+
+// finish set the dirs mode and mtime. reverse order for set the sub dirs first.
+func (e *extractor) finish() error {
+	for i := len(e.dirs) - 1; i >= 0; i-- {
+		dm := e.dirs[i]
+		if dm.mode == 0 {
+			continue
+		}
+		if err := os.Chmod(dm.path, dm.mode); err != nil {
+			return err
+		}
+		if err := os.Chtimes(dm.path, dm.mtime, dm.mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
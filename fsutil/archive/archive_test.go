@@ -0,0 +1,202 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/fsutil/archive"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func makeSrcDir(t *testing.T) string {
+	src := filepath.Join(t.TempDir(), "src")
+	fsutil.Must2(fsutil.PutContents(src+"/a.txt", "hello"))
+	fsutil.Must2(fsutil.PutContents(src+"/sub/b.txt", "world"))
+	fsutil.Must2(fsutil.PutContents(src+"/sub/c.log", "log"))
+
+	mtime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoErr(t, os.Chtimes(src+"/a.txt", mtime, mtime))
+	assert.NoErr(t, os.Chmod(src+"/a.txt", 0600))
+	return src
+}
+
+func TestCreateZip(t *testing.T) {
+	src := makeSrcDir(t)
+	tmp := t.TempDir()
+	zipFile := tmp + "/test.zip"
+
+	assert.NoErr(t, archive.CreateZip(zipFile, src, archive.WithFilters(fsutil.ExcludeSuffix(".log"))))
+	assert.True(t, fsutil.IsZipFile(zipFile))
+
+	dst := tmp + "/dst"
+	assert.NoErr(t, archive.ExtractZip(zipFile, dst))
+	assert.Eq(t, "hello", fsutil.ReadString(dst+"/a.txt"))
+	assert.Eq(t, "world", fsutil.ReadString(dst+"/sub/b.txt"))
+	assert.False(t, fsutil.PathExists(dst+"/sub/c.log"))
+
+	fi, err := os.Stat(dst + "/a.txt")
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(1672628645), fi.ModTime().Unix())
+	if runtime.GOOS != "windows" {
+		assert.Eq(t, os.FileMode(0600), fi.Mode().Perm())
+	}
+
+	// extract with filters
+	dst2 := tmp + "/dst2"
+	assert.NoErr(t, archive.ExtractZip(zipFile, dst2, archive.WithFilters(fsutil.OnlyFindDir)))
+	assert.True(t, fsutil.IsDir(dst2+"/sub"))
+	assert.False(t, fsutil.PathExists(dst2+"/a.txt"))
+
+	// stream
+	buf := new(bytes.Buffer)
+	assert.NoErr(t, archive.WriteZip(buf, src+"/a.txt"))
+	dst3 := tmp + "/dst3"
+	assert.NoErr(t, archive.ExtractZipFrom(bytes.NewReader(buf.Bytes()), int64(buf.Len()), dst3))
+	assert.Eq(t, "hello", fsutil.ReadString(dst3+"/a.txt"))
+
+	assert.Err(t, archive.CreateZip(tmp+"/err.zip", src+"/not-exist"))
+	assert.Err(t, archive.ExtractZip(tmp+"/not-exist.zip", dst))
+}
+
+func TestCreateTarGz(t *testing.T) {
+	src := makeSrcDir(t)
+	if runtime.GOOS != "windows" {
+		assert.NoErr(t, os.Symlink("a.txt", src+"/link.txt"))
+	}
+
+	tmp := t.TempDir()
+	tgzFile := tmp + "/test.tar.gz"
+	assert.NoErr(t, archive.CreateTarGz(tgzFile, src))
+
+	dst := tmp + "/dst"
+	assert.NoErr(t, archive.ExtractTarGz(tgzFile, dst, archive.WithFilters(fsutil.ExcludeSuffix(".log"))))
+	assert.Eq(t, "hello", fsutil.ReadString(dst+"/a.txt"))
+	assert.Eq(t, "world", fsutil.ReadString(dst+"/sub/b.txt"))
+	assert.False(t, fsutil.PathExists(dst+"/sub/c.log"))
+
+	fi, err := os.Stat(dst + "/a.txt")
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(1672628645), fi.ModTime().Unix())
+	if runtime.GOOS != "windows" {
+		assert.Eq(t, os.FileMode(0600), fi.Mode().Perm())
+		assert.True(t, fsutil.IsSymlink(dst+"/link.txt"))
+		assert.Eq(t, "hello", fsutil.ReadString(dst+"/link.txt"))
+	}
+
+	// size limits
+	err = archive.ExtractTarGz(tgzFile, tmp+"/dst2", archive.WithLimits(0, 3, 0))
+	assert.True(t, errors.Is(err, archive.ErrTooLarge))
+	err = archive.ExtractTarGz(tgzFile, tmp+"/dst3", archive.WithLimits(0, 0, 8))
+	assert.True(t, errors.Is(err, archive.ErrTooLarge))
+	err = archive.ExtractTarGz(tgzFile, tmp+"/dst4", archive.WithLimits(2, 0, 0))
+	assert.True(t, errors.Is(err, archive.ErrTooLarge))
+
+	// the small files together exceed the total size
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	addZipEntry(t, zw, "a.txt", strings.Repeat("a", 10), 0644)
+	addZipEntry(t, zw, "b.txt", strings.Repeat("b", 1024), 0644)
+	addZipEntry(t, zw, "c.txt", "c", 0644)
+	assert.NoErr(t, zw.Close())
+	err = archive.ExtractZipFrom(bytes.NewReader(buf.Bytes()), int64(buf.Len()), tmp+"/dst6", archive.WithLimits(0, 0, 10))
+	assert.True(t, errors.Is(err, archive.ErrTooLarge))
+	assert.Eq(t, 10, len(fsutil.ReadString(tmp+"/dst6/a.txt")))
+	assert.False(t, fsutil.PathExists(tmp+"/dst6/b.txt"))
+	assert.False(t, fsutil.PathExists(tmp+"/dst6/c.txt"))
+
+	assert.Err(t, archive.ExtractTarGzFrom(strings.NewReader("invalid"), tmp+"/dst5"))
+}
+
+func TestExtract_zipSlip(t *testing.T) {
+	tmp := t.TempDir()
+
+	// zip with illegal path
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("../evil.txt")
+	assert.NoErr(t, err)
+	_, err = w.Write([]byte("evil"))
+	assert.NoErr(t, err)
+	assert.NoErr(t, zw.Close())
+
+	err = archive.ExtractZipFrom(bytes.NewReader(buf.Bytes()), int64(buf.Len()), tmp+"/dst")
+	assert.True(t, errors.Is(err, archive.ErrIllegalPath))
+	assert.False(t, fsutil.PathExists(tmp+"/evil.txt"))
+
+	// tar with symlink to outside
+	buf.Reset()
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	assert.NoErr(t, tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../etc"}))
+	assert.NoErr(t, tw.Close())
+	assert.NoErr(t, gw.Close())
+
+	err = archive.ExtractTarGzFrom(buf, tmp+"/dst")
+	assert.True(t, errors.Is(err, archive.ErrIllegalPath))
+}
+
+func TestExtract_chainedSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+	}
+
+	tmp := t.TempDir()
+	// "a" -> ".", then "a/b" -> "..", the "a/b/escaped.txt" will be write to outside.
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	assert.NoErr(t, tw.WriteHeader(&tar.Header{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."}))
+	assert.NoErr(t, tw.WriteHeader(&tar.Header{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."}))
+	assert.NoErr(t, tw.WriteHeader(&tar.Header{Name: "a/b/escaped.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 4}))
+	_, err := tw.Write([]byte("evil"))
+	assert.NoErr(t, err)
+	assert.NoErr(t, tw.Close())
+	assert.NoErr(t, gw.Close())
+
+	err = archive.ExtractTarGzFrom(buf, tmp+"/dst")
+	assert.True(t, errors.Is(err, archive.ErrIllegalPath))
+	assert.False(t, fsutil.PathExists(tmp+"/escaped.txt"))
+
+	// same for zip
+	buf.Reset()
+	zw := zip.NewWriter(buf)
+	addZipEntry(t, zw, "a", ".", fs.ModeSymlink|0777)
+	addZipEntry(t, zw, "a/b", "..", fs.ModeSymlink|0777)
+	addZipEntry(t, zw, "a/b/escaped.txt", "evil", 0644)
+	assert.NoErr(t, zw.Close())
+
+	err = archive.ExtractZipFrom(bytes.NewReader(buf.Bytes()), int64(buf.Len()), tmp+"/dst2")
+	assert.True(t, errors.Is(err, archive.ErrIllegalPath))
+	assert.False(t, fsutil.PathExists(tmp+"/escaped.txt"))
+
+	// write file through an exists symlink: "c" -> "." then "c/d.txt" is allowed
+	buf.Reset()
+	zw = zip.NewWriter(buf)
+	addZipEntry(t, zw, "c", ".", fs.ModeSymlink|0777)
+	addZipEntry(t, zw, "c/d.txt", "data", 0644)
+	assert.NoErr(t, zw.Close())
+
+	err = archive.ExtractZipFrom(bytes.NewReader(buf.Bytes()), int64(buf.Len()), tmp+"/dst3")
+	assert.NoErr(t, err)
+	assert.Eq(t, "data", fsutil.ReadString(tmp+"/dst3/d.txt"))
+}
+
+func addZipEntry(t *testing.T, zw *zip.Writer, name, contents string, mode fs.FileMode) {
+	hdr := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	hdr.SetMode(mode)
+	w, err := zw.CreateHeader(hdr)
+	assert.NoErr(t, err)
+	_, err = w.Write([]byte(contents))
+	assert.NoErr(t, err)
+}
@@ -0,0 +1,133 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gookit/goutil/fsutil"
+)
+
+// CreateTarGz create a tar.gz archive file from the src file or dir.
+//
+// Usage:
+//
+//	err := archive.CreateTarGz("path/to/dist.tar.gz", "path/to/build")
+func CreateTarGz(dstFile, src string, optFns ...OptionFunc) error {
+	fh, err := fsutil.OpenTruncFile(dstFile, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = WriteTarGz(fh, src, optFns...)
+	if err1 := fh.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// WriteTarGz write the src file or dir as tar.gz archive to the writer.
+func WriteTarGz(w io.Writer, src string, optFns ...OptionFunc) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	err := walkSrc(src, NewOptions(optFns...), func(se *srcEntry) error {
+		hdr, err := tar.FileInfoHeader(se.info, se.link)
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = se.name
+		if se.info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !se.info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileTo(tw, se.path)
+	})
+
+	if err1 := tw.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	if err1 := gw.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// ExtractTarGz extract the tar.gz archive file to dst dir.
+//
+// Usage:
+//
+//	err := archive.ExtractTarGz("path/to/dist.tar.gz", "path/to/dir")
+func ExtractTarGz(srcFile, dstDir string, optFns ...OptionFunc) error {
+	fh, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	return ExtractTarGzFrom(fh, dstDir, optFns...)
+}
+
+// ExtractTarGzFrom extract the tar.gz archive from reader to dst dir.
+func ExtractTarGzFrom(r io.Reader, dstDir string, optFns ...OptionFunc) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	ex, err := newExtractor(dstDir, NewOptions(optFns...))
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		name := strings.TrimSuffix(hdr.Name, "/")
+		if name == "" || name == "." {
+			continue
+		}
+
+		dstPath, skip, err := ex.target(name, hdr.FileInfo())
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+
+		mode := hdr.FileInfo().Mode()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = ex.mkdir(dstPath, mode, hdr.ModTime)
+		case tar.TypeSymlink:
+			err = ex.symlink(dstPath, hdr.Linkname)
+		case tar.TypeReg:
+			err = ex.writeFile(dstPath, tr, mode, hdr.ModTime)
+		}
+		// skip other types. eg: hard link, device
+
+		if err != nil {
+			return err
+		}
+	}
+	return ex.finish()
+}
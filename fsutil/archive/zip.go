@@ -0,0 +1,160 @@
+package archive
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gookit/goutil/fsutil"
+)
+
+// CreateZip create a zip archive file from the src file or dir.
+//
+// Usage:
+//
+//	err := archive.CreateZip("path/to/dist.zip", "path/to/build")
+func CreateZip(dstFile, src string, optFns ...OptionFunc) error {
+	fh, err := fsutil.OpenTruncFile(dstFile, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = WriteZip(fh, src, optFns...)
+	if err1 := fh.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// WriteZip write the src file or dir as zip archive to the writer.
+func WriteZip(w io.Writer, src string, optFns ...OptionFunc) error {
+	zw := zip.NewWriter(w)
+	err := walkSrc(src, NewOptions(optFns...), func(se *srcEntry) error {
+		hdr, err := zip.FileInfoHeader(se.info)
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = se.name
+		if se.info.IsDir() {
+			hdr.Name += "/"
+		} else {
+			hdr.Method = zip.Deflate
+		}
+
+		ew, err := zw.CreateHeader(hdr)
+		if err != nil || se.info.IsDir() {
+			return err
+		}
+
+		// symlink: store the link target as contents
+		if se.link != "" {
+			_, err = io.WriteString(ew, se.link)
+			return err
+		}
+		return copyFileTo(ew, se.path)
+	})
+
+	if err1 := zw.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// ExtractZip extract the zip archive file to dst dir.
+//
+// Usage:
+//
+//	err := archive.ExtractZip("path/to/dist.zip", "path/to/dir", archive.WithLimits(1000, 0, 1<<30))
+func ExtractZip(srcFile, dstDir string, optFns ...OptionFunc) error {
+	zr, err := zip.OpenReader(srcFile)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	return extractZip(&zr.Reader, dstDir, NewOptions(optFns...))
+}
+
+// ExtractZipFrom extract the zip archive from reader to dst dir.
+func ExtractZipFrom(r io.ReaderAt, size int64, dstDir string, optFns ...OptionFunc) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	return extractZip(zr, dstDir, NewOptions(optFns...))
+}
+
+func extractZip(zr *zip.Reader, dstDir string, opt *Options) error {
+	ex, err := newExtractor(dstDir, opt)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		name := strings.TrimSuffix(f.Name, "/")
+		fi := f.FileInfo()
+
+		dstPath, skip, err := ex.target(name, fi)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+
+		mode := fi.Mode()
+		switch {
+		case mode.IsDir():
+			err = ex.mkdir(dstPath, mode, f.Modified)
+		case mode&fs.ModeSymlink != 0:
+			err = extractZipSymlink(ex, f, dstPath)
+		default:
+			err = extractZipFile(ex, f, dstPath)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return ex.finish()
+}
+
+func extractZipFile(ex *extractor, f *zip.File, dstPath string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return ex.writeFile(dstPath, rc, f.Mode(), f.Modified)
+}
+
+func extractZipSymlink(ex *extractor, f *zip.File, dstPath string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// the link target should not be large
+	bs, err := io.ReadAll(io.LimitReader(rc, 4096))
+	if err != nil {
+		return err
+	}
+	return ex.symlink(dstPath, path.Clean(string(bs)))
+}
+
+func copyFileTo(w io.Writer, fPath string) error {
+	fh, err := os.Open(fPath)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	_, err = io.Copy(w, fh)
+	return err
+}
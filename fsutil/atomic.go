@@ -0,0 +1,252 @@
+package fsutil
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ************************************************************
+//	atomic write files
+// ************************************************************
+
+// AtomicOption for atomic write file
+type AtomicOption struct {
+	// Perm for create new file. default is DefaultFilePerm
+	//
+	// If the target file exists, will keep the mode of the old file.
+	Perm os.FileMode
+	// DirPerm for create the parent dir. default is DefaultDirPerm
+	DirPerm os.FileMode
+	// NoSync dont call fsync for the temp file and parent dir. default is false
+	NoSync bool
+}
+
+// AtomicOptionFunc for atomic write file
+type AtomicOptionFunc func(opt *AtomicOption)
+
+// NewAtomicOption create a new AtomicOption instance
+func NewAtomicOption(optFns ...AtomicOptionFunc) *AtomicOption {
+	opt := &AtomicOption{
+		Perm:    DefaultFilePerm,
+		DirPerm: DefaultDirPerm,
+	}
+
+	for _, fn := range optFns {
+		fn(opt)
+	}
+	return opt
+}
+
+// WithAtomicPerm set file perm for atomic write new file
+func WithAtomicPerm(perm os.FileMode) AtomicOptionFunc {
+	return func(opt *AtomicOption) {
+		opt.Perm = perm
+	}
+}
+
+// WithoutSync disable fsync on atomic write file
+func WithoutSync(opt *AtomicOption) {
+	opt.NoSync = true
+}
+
+// AtomicWriter write contents to a temp file in the same dir of the target file,
+// then rename it to the target file on Close(). so the target file never be half-written.
+//
+// Usage:
+//
+//	w, err := fsutil.NewAtomicWriter("path/to/config.json")
+//	if err != nil {
+//		return err
+//	}
+//	defer w.Abort() // will do nothing after Close()
+//
+//	// write contents to w ...
+//	return w.Close()
+type AtomicWriter struct {
+	opt  *AtomicOption
+	path string
+	tmp  *os.File
+	// old file info of the target file. is nil if not exists.
+	old os.FileInfo
+	// closed mark. on Close() or Abort()
+	closed bool
+}
+
+// NewAtomicWriter create a new AtomicWriter instance for the target file path.
+// will auto create parent dir.
+//
+// If the target is a symlink, will write to the real file that the link points to, the link is kept.
+func NewAtomicWriter(fPath string, optFns ...AtomicOptionFunc) (*AtomicWriter, error) {
+	opt := NewAtomicOption(optFns...)
+
+	// resolve the symlink, otherwise the rename will replace the link itself.
+	if fi, err := os.Lstat(fPath); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		realPath, err := filepath.EvalSymlinks(fPath)
+		if err != nil {
+			return nil, err
+		}
+		fPath = realPath
+	}
+
+	dir, name := filepath.Split(fPath)
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, opt.DirPerm); err != nil {
+		return nil, err
+	}
+
+	old, err := os.Stat(fPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		old = nil
+	} else if !old.Mode().IsRegular() {
+		return nil, &os.PathError{Op: "atomic write", Path: fPath, Err: errors.New("target is not a regular file")}
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AtomicWriter{
+		opt:  opt,
+		path: fPath,
+		tmp:  tmp,
+		old:  old,
+	}, nil
+}
+
+// Path get the target file path
+func (w *AtomicWriter) Path() string { return w.path }
+
+// TempPath get the temp file path
+func (w *AtomicWriter) TempPath() string { return w.tmp.Name() }
+
+// Write data to the temp file
+func (w *AtomicWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	return w.tmp.Write(p)
+}
+
+// WriteString write string to the temp file
+func (w *AtomicWriter) WriteString(s string) (int, error) {
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	return w.tmp.WriteString(s)
+}
+
+// ReadFrom read data from reader and write to the temp file
+func (w *AtomicWriter) ReadFrom(r io.Reader) (int64, error) {
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	return io.Copy(w.tmp, r)
+}
+
+// Close commit the written contents: fsync the temp file, keep mode and owner
+// of the old file, rename the temp file to target and fsync the parent dir.
+//
+// On error, the temp file will be removed and the target file is unchanged.
+func (w *AtomicWriter) Close() (err error) {
+	if w.closed {
+		return os.ErrClosed
+	}
+	w.closed = true
+
+	tmpPath := w.tmp.Name()
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if !w.opt.NoSync {
+		if err = w.tmp.Sync(); err != nil {
+			_ = w.tmp.Close()
+			return err
+		}
+	}
+	if err = w.tmp.Close(); err != nil {
+		return err
+	}
+
+	perm := w.opt.Perm
+	if w.old != nil {
+		perm = w.old.Mode().Perm()
+		keepOwner(tmpPath, w.old)
+	}
+	if err = os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpPath, w.path); err != nil {
+		return err
+	}
+
+	if !w.opt.NoSync {
+		return syncDir(filepath.Dir(w.path))
+	}
+	return nil
+}
+
+// Abort discard the written contents and remove the temp file.
+// will do nothing if the writer has been closed.
+func (w *AtomicWriter) Abort() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	_ = w.tmp.Close()
+	return os.Remove(w.tmp.Name())
+}
+
+// AtomicWrite write contents to file atomically. Will auto create dir.
+//
+// data type allows: string, []byte, io.Reader
+//
+// Usage:
+//
+//	err := fsutil.AtomicWrite("path/to/config.json", contents)
+func AtomicWrite(fPath string, data any, optFns ...AtomicOptionFunc) error {
+	w, err := NewAtomicWriter(fPath, optFns...)
+	if err != nil {
+		return err
+	}
+
+	switch typData := data.(type) {
+	case []byte:
+		_, err = w.Write(typData)
+	case string:
+		_, err = w.WriteString(typData)
+	case io.Reader: // eg: buffer
+		_, err = w.ReadFrom(typData)
+	default:
+		_ = w.Abort()
+		panic("AtomicWrite: data type only allow: []byte, string, io.Reader")
+	}
+
+	if err != nil {
+		_ = w.Abort()
+		return err
+	}
+	return w.Close()
+}
+
+// UpdateContentsAtomic read file contents, call handleFn(contents) handle,
+// then write updated contents to file atomically. see AtomicWrite()
+func UpdateContentsAtomic(filePath string, handleFn func(bs []byte) []byte, optFns ...AtomicOptionFunc) error {
+	bs, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return AtomicWrite(filePath, handleFn(bs), optFns...)
+}
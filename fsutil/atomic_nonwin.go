@@ -0,0 +1,19 @@
+//go:build !windows
+
+package fsutil
+
+import "os"
+
+// syncDir fsync the dir, make sure the rename is persisted.
+func syncDir(dirPath string) error {
+	dir, err := os.Open(dirPath)
+	if err != nil {
+		return err
+	}
+
+	err = dir.Sync()
+	if err1 := dir.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
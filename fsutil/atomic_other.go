@@ -0,0 +1,8 @@
+//go:build !unix
+
+package fsutil
+
+import "os"
+
+// keepOwner on the non-unix OS is not supported. eg: windows, plan9
+func keepOwner(_ string, _ os.FileInfo) {}
@@ -0,0 +1,112 @@
+package fsutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestAtomicWrite(t *testing.T) {
+	fPath := filepath.Join(t.TempDir(), "sub/atomic.txt")
+
+	assert.NoErr(t, fsutil.AtomicWrite(fPath, "hello", fsutil.WithAtomicPerm(0640)))
+	assert.Eq(t, "hello", fsutil.ReadString(fPath))
+	if runtime.GOOS != "windows" {
+		assert.Eq(t, "-rw-r-----", fileMode(t, fPath))
+	}
+
+	// keep mode of the old file
+	assert.NoErr(t, os.Chmod(fPath, 0600))
+	assert.NoErr(t, fsutil.AtomicWrite(fPath, []byte("hello world")))
+	assert.Eq(t, "hello world", fsutil.ReadString(fPath))
+	if runtime.GOOS != "windows" {
+		assert.Eq(t, "-rw-------", fileMode(t, fPath))
+	}
+
+	assert.NoErr(t, fsutil.AtomicWrite(fPath, strings.NewReader("from reader"), fsutil.WithoutSync))
+	assert.Eq(t, "from reader", fsutil.ReadString(fPath))
+
+	err := fsutil.UpdateContentsAtomic(fPath, func(bs []byte) []byte {
+		return append(bs, " updated"...)
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, "from reader updated", fsutil.ReadString(fPath))
+
+	// no temp files left
+	ents, err := os.ReadDir(filepath.Dir(fPath))
+	assert.NoErr(t, err)
+	assert.Len(t, ents, 1)
+
+	assert.Panics(t, func() {
+		_ = fsutil.AtomicWrite(fPath, 23)
+	})
+	assert.Err(t, fsutil.AtomicWrite(filepath.Dir(fPath), "data"))
+	assert.Err(t, fsutil.UpdateContentsAtomic(fPath+".not-exist", nil))
+}
+
+func TestAtomicWriter(t *testing.T) {
+	fPath := filepath.Join(t.TempDir(), "atomic-writer.txt")
+	assert.NoErr(t, fsutil.AtomicWrite(fPath, "old"))
+
+	w, err := fsutil.NewAtomicWriter(fPath)
+	assert.NoErr(t, err)
+	assert.Eq(t, fPath, w.Path())
+	assert.True(t, fsutil.IsFile(w.TempPath()))
+
+	_, err = w.WriteString("new contents")
+	assert.NoErr(t, err)
+	// target not changed before close
+	assert.Eq(t, "old", fsutil.ReadString(fPath))
+
+	assert.NoErr(t, w.Close())
+	assert.Eq(t, "new contents", fsutil.ReadString(fPath))
+	assert.False(t, fsutil.PathExists(w.TempPath()))
+	assert.Err(t, w.Close())
+	assert.NoErr(t, w.Abort())
+	_, err = w.Write([]byte("data"))
+	assert.Err(t, err)
+
+	// abort
+	w, err = fsutil.NewAtomicWriter(fPath)
+	assert.NoErr(t, err)
+	_, err = w.Write([]byte("discard"))
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Abort())
+	assert.Eq(t, "new contents", fsutil.ReadString(fPath))
+	assert.False(t, fsutil.PathExists(w.TempPath()))
+}
+
+func fileMode(t *testing.T, fPath string) string {
+	fi, err := os.Stat(fPath)
+	assert.NoErr(t, err)
+	return fi.Mode().String()
+}
+
+func TestAtomicWrite_symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+	}
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real.txt")
+	link := filepath.Join(dir, "link.txt")
+	assert.NoErr(t, os.WriteFile(target, []byte("old"), 0644))
+	assert.NoErr(t, os.Symlink("real.txt", link))
+
+	// write to the link target, the link is kept
+	assert.NoErr(t, fsutil.AtomicWrite(link, "new"))
+	assert.True(t, fsutil.IsSymlink(link))
+	assert.Eq(t, "new", fsutil.ReadString(target))
+
+	err := fsutil.UpdateContentsAtomic(link, func(bs []byte) []byte {
+		return append(bs, " updated"...)
+	})
+	assert.NoErr(t, err)
+	assert.True(t, fsutil.IsSymlink(link))
+	assert.Eq(t, "new updated", fsutil.ReadString(target))
+}
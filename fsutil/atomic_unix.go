@@ -0,0 +1,15 @@
+//go:build unix
+
+package fsutil
+
+import (
+	"os"
+	"syscall"
+)
+
+// keepOwner keep the owner of the old file. will ignore error, eg: not permitted
+func keepOwner(fPath string, old os.FileInfo) {
+	if st, ok := old.Sys().(*syscall.Stat_t); ok {
+		_ = os.Chown(fPath, int(st.Uid), int(st.Gid))
+	}
+}
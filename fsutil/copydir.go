@@ -0,0 +1,418 @@
+package fsutil
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gookit/goutil/x/encodes/hashutil"
+)
+
+// ************************************************************
+//	copy, sync and move dirs
+// ************************************************************
+
+// CompareMode for check the dst file is unchanged on copy dir.
+type CompareMode uint8
+
+// compare modes for skip unchanged files on copy dir.
+const (
+	CompareNone      CompareMode = iota // always copy file
+	CompareSizeMtime                    // skip on size and mtime are same
+	CompareHash                         // skip on size and sha256 hash are same
+)
+
+// CopyOption for copy/sync dir
+type CopyOption struct {
+	// Filters for filter the src file or dir. return false will skip it.
+	//
+	// the fPath is full path of the src file or dir.
+	Filters []FilterFunc
+	// KeepMode preserve the file and dir mode. default is true
+	KeepMode bool
+	// KeepMtime preserve the file and dir mod time. default is false
+	KeepMtime bool
+	// KeepSymlink copy symlinks as symlinks, otherwise copy the target file. default is false
+	KeepSymlink bool
+	// Compare mode for skip unchanged files. default is CompareNone
+	Compare CompareMode
+	// DryRun dont really copy, only collect the paths to CopyResult
+	DryRun bool
+	// Delete remove the dst files that not exist in src. SyncDir will enable it.
+	Delete bool
+}
+
+// CopyOptionFunc for copy/sync dir
+type CopyOptionFunc func(opt *CopyOption)
+
+// NewCopyOption create a new CopyOption instance
+func NewCopyOption(optFns ...CopyOptionFunc) *CopyOption {
+	opt := &CopyOption{KeepMode: true}
+	for _, fn := range optFns {
+		fn(opt)
+	}
+	return opt
+}
+
+// WithFilters add filters for copy dir. see FilterFunc
+func WithFilters(fns ...FilterFunc) CopyOptionFunc {
+	return func(opt *CopyOption) {
+		opt.Filters = append(opt.Filters, fns...)
+	}
+}
+
+// WithCompare set the compare mode for skip unchanged files
+func WithCompare(mode CompareMode) CopyOptionFunc {
+	return func(opt *CopyOption) {
+		opt.Compare = mode
+	}
+}
+
+// WithPreserve preserve mode, mtime and symlinks on copy. like `cp -a`
+func WithPreserve(opt *CopyOption) {
+	opt.KeepMode = true
+	opt.KeepMtime = true
+	opt.KeepSymlink = true
+}
+
+// WithDryRun only collect the copy paths, dont really copy.
+func WithDryRun(opt *CopyOption) {
+	opt.DryRun = true
+}
+
+// CopyResult for copy/sync dir. all paths are the dst paths.
+type CopyResult struct {
+	// Copied file or symlink paths. on dry-run, is the paths will be copied.
+	Copied []string
+	// Skipped unchanged file paths
+	Skipped []string
+	// Removed paths of dst by sync. on dry-run, is the paths will be removed.
+	Removed []string
+}
+
+// CopyDir copy all files and sub-dirs of the src dir to dst dir. will auto create dst dir.
+//
+// Usage:
+//
+//	// like: cp -r src dst
+//	res, err := fsutil.CopyDir("path/to/src", "path/to/dst")
+//	// with filters, preserve mode, mtime and symlinks
+//	res, err := fsutil.CopyDir(src, dst, fsutil.WithPreserve, fsutil.WithFilters(fsutil.ExcludeDotFile))
+func CopyDir(srcDir, dstDir string, optFns ...CopyOptionFunc) (*CopyResult, error) {
+	return copyDir(srcDir, dstDir, NewCopyOption(optFns...))
+}
+
+// SyncDir sync the src dir to dst dir, like `rsync -a --delete`.
+//
+//   - skip unchanged files by size and mtime(default)
+//   - remove the dst files that not exist in src. the filtered paths will be kept.
+func SyncDir(srcDir, dstDir string, optFns ...CopyOptionFunc) (*CopyResult, error) {
+	opt := &CopyOption{Compare: CompareSizeMtime}
+	WithPreserve(opt)
+	for _, fn := range optFns {
+		fn(opt)
+	}
+
+	opt.Delete = true
+	return copyDir(srcDir, dstDir, opt)
+}
+
+func copyDir(srcDir, dstDir string, opt *CopyOption) (*CopyResult, error) {
+	fi, err := os.Stat(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, &os.PathError{Op: "copy dir", Path: srcDir, Err: errors.New("src is not a dir")}
+	}
+
+	// like `cp -r`, cannot copy a dir into itself. otherwise will recurse until the disk is full.
+	if isSubPath(realPath(srcDir), realPath(dstDir)) {
+		return nil, &os.PathError{Op: "copy dir", Path: dstDir, Err: errors.New("cannot copy a dir into itself")}
+	}
+
+	res := &CopyResult{}
+	if err = copyDirEntry(srcDir, dstDir, fi, opt, res, nil); err != nil {
+		return res, err
+	}
+
+	if opt.Delete {
+		err = removeExtraneous(srcDir, dstDir, opt, res)
+	}
+	return res, err
+}
+
+// get the absolute path, and resolve the symlinks if the path exists.
+func realPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return path
+}
+
+// check the sub path is equals to or under the base path
+func isSubPath(base, sub string) bool {
+	if base == sub {
+		return true
+	}
+	return strings.HasPrefix(sub, strings.TrimSuffix(base, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// parents: the real paths of the parent dirs, for check the symlink loop on follow symlinks.
+func copyDirEntry(srcDir, dstDir string, fi fs.FileInfo, opt *CopyOption, res *CopyResult, parents []string) error {
+	// a followed symlink points to the parent dir, will recurse forever.
+	if !opt.KeepSymlink {
+		real := realPath(srcDir)
+		for _, parent := range parents {
+			if parent == real {
+				return &os.PathError{Op: "copy dir", Path: srcDir, Err: errors.New("symlink loop detected")}
+			}
+		}
+		parents = append(parents, real)
+	}
+
+	if !opt.DryRun {
+		if err := os.MkdirAll(dstDir, DefaultDirPerm); err != nil {
+			return err
+		}
+	}
+
+	des, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+
+	for _, ent := range des {
+		srcPath := filepath.Join(srcDir, ent.Name())
+		if len(opt.Filters) > 0 && ApplyFilters(srcPath, ent, opt.Filters) {
+			continue
+		}
+
+		info, err := ent.Info()
+		if err != nil {
+			return err
+		}
+
+		dstPath := filepath.Join(dstDir, ent.Name())
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if opt.KeepSymlink {
+				if err = copySymlink(srcPath, dstPath, opt, res); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// follow the symlink
+			if info, err = os.Stat(srcPath); err != nil {
+				return err
+			}
+		}
+
+		if info.IsDir() {
+			err = copyDirEntry(srcPath, dstPath, info, opt, res, parents)
+		} else if info.Mode().IsRegular() {
+			err = copyFileEntry(srcPath, dstPath, info, opt, res)
+		}
+		// skip other types. eg: socket, device
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if opt.DryRun {
+		return nil
+	}
+
+	// set dir mode and mtime after contents copied
+	if opt.KeepMode {
+		if err = os.Chmod(dstDir, fi.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if opt.KeepMtime {
+		return os.Chtimes(dstDir, fi.ModTime(), fi.ModTime())
+	}
+	return nil
+}
+
+func copyFileEntry(srcPath, dstPath string, fi fs.FileInfo, opt *CopyOption, res *CopyResult) error {
+	if opt.Compare != CompareNone && isSameFile(srcPath, dstPath, fi, opt.Compare) {
+		res.Skipped = append(res.Skipped, dstPath)
+		return nil
+	}
+
+	res.Copied = append(res.Copied, dstPath)
+	if opt.DryRun {
+		return nil
+	}
+
+	perm := DefaultFilePerm
+	if opt.KeepMode {
+		perm = fi.Mode().Perm()
+	}
+	if err := copyRegularFile(srcPath, dstPath, perm, opt.KeepMode); err != nil {
+		return err
+	}
+
+	if opt.KeepMtime {
+		return os.Chtimes(dstPath, fi.ModTime(), fi.ModTime())
+	}
+	return nil
+}
+
+func copySymlink(srcPath, dstPath string, opt *CopyOption, res *CopyResult) error {
+	target, err := os.Readlink(srcPath)
+	if err != nil {
+		return err
+	}
+
+	if opt.Compare != CompareNone {
+		if old, err := os.Readlink(dstPath); err == nil && old == target {
+			res.Skipped = append(res.Skipped, dstPath)
+			return nil
+		}
+	}
+
+	res.Copied = append(res.Copied, dstPath)
+	if opt.DryRun {
+		return nil
+	}
+
+	if _, err = os.Lstat(dstPath); err == nil {
+		if err = os.RemoveAll(dstPath); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(target, dstPath)
+}
+
+// copyRegularFile copy file contents, and chmod the dst file if chmod is true.
+func copyRegularFile(srcPath, dstPath string, perm fs.FileMode, chmod bool) error {
+	srcFile, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dstPath, FsCWTFlags, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dstFile, srcFile)
+	if err1 := dstFile.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+
+	// the perm on open file will be masked by umask
+	if err == nil && chmod {
+		err = os.Chmod(dstPath, perm)
+	}
+	return err
+}
+
+// isSameFile check the dst file is same as src file by compare mode
+func isSameFile(srcPath, dstPath string, srcFi fs.FileInfo, mode CompareMode) bool {
+	dstFi, err := os.Stat(dstPath)
+	if err != nil || !dstFi.Mode().IsRegular() || dstFi.Size() != srcFi.Size() {
+		return false
+	}
+
+	if mode == CompareSizeMtime {
+		// some filesystems not support nanosecond mtime
+		return dstFi.ModTime().Truncate(time.Second).Equal(srcFi.ModTime().Truncate(time.Second))
+	}
+
+	srcSum, err := FileHash(srcPath, hashutil.AlgoSHA256)
+	if err != nil {
+		return false
+	}
+	dstSum, err := FileHash(dstPath, hashutil.AlgoSHA256)
+	return err == nil && srcSum == dstSum
+}
+
+// removeExtraneous remove the dst paths that not exist in src dir.
+func removeExtraneous(srcDir, dstDir string, opt *CopyOption, res *CopyResult) error {
+	if opt.DryRun && !IsDir(dstDir) {
+		return nil
+	}
+
+	return filepath.WalkDir(dstDir, func(dstPath string, ent fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if dstPath == dstDir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dstDir, dstPath)
+		if err != nil {
+			return err
+		}
+
+		srcPath := filepath.Join(srcDir, rel)
+		if _, err = os.Lstat(srcPath); err == nil {
+			return nil
+		}
+
+		// keep the filtered paths
+		if len(opt.Filters) > 0 && ApplyFilters(srcPath, ent, opt.Filters) {
+			return skipEntry(ent)
+		}
+
+		res.Removed = append(res.Removed, dstPath)
+		if !opt.DryRun {
+			if err = os.RemoveAll(dstPath); err != nil {
+				return err
+			}
+		}
+		return skipEntry(ent)
+	})
+}
+
+func skipEntry(ent fs.DirEntry) error {
+	if ent.IsDir() {
+		return fs.SkipDir
+	}
+	return nil
+}
+
+// Move a file or dir to the dst path. like the `mv` command.
+//
+// If rename failed on cross device, will copy the src to dst with
+// preserve mode, mtime and symlinks, then remove the src.
+func Move(srcPath, dstPath string) error {
+	err := os.Rename(srcPath, dstPath)
+	if err == nil || !isCrossDevice(err) {
+		return err
+	}
+
+	fi, err := os.Lstat(srcPath)
+	if err != nil {
+		return err
+	}
+
+	opt := NewCopyOption(WithPreserve)
+	res := &CopyResult{}
+	switch {
+	case fi.IsDir():
+		err = copyDirEntry(srcPath, dstPath, fi, opt, res, nil)
+	case fi.Mode()&fs.ModeSymlink != 0:
+		err = copySymlink(srcPath, dstPath, opt, res)
+	default:
+		err = copyFileEntry(srcPath, dstPath, fi, opt, res)
+	}
+
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(srcPath)
+}
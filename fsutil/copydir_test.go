@@ -0,0 +1,147 @@
+package fsutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func makeCopySrc(t *testing.T) string {
+	src := filepath.Join(t.TempDir(), "src")
+	fsutil.Must2(fsutil.PutContents(src+"/a.txt", "a"))
+	fsutil.Must2(fsutil.PutContents(src+"/sub/b.txt", "b"))
+	fsutil.Must2(fsutil.PutContents(src+"/sub/c.log", "c"))
+	fsutil.Must2(fsutil.PutContents(src+"/.git/config", "git"))
+	return src
+}
+
+func TestCopyDir(t *testing.T) {
+	src := makeCopySrc(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	res, err := fsutil.CopyDir(src, dst, fsutil.WithFilters(fsutil.ExcludeDotFile, fsutil.ExcludeSuffix(".log")))
+	assert.NoErr(t, err)
+	assert.Len(t, res.Copied, 2)
+	assert.Eq(t, "a", fsutil.ReadString(dst+"/a.txt"))
+	assert.Eq(t, "b", fsutil.ReadString(dst+"/sub/b.txt"))
+	assert.False(t, fsutil.PathExists(dst+"/sub/c.log"))
+	assert.False(t, fsutil.PathExists(dst+"/.git"))
+
+	// dry run
+	dst2 := filepath.Join(t.TempDir(), "dst2")
+	res, err = fsutil.CopyDir(src, dst2, fsutil.WithDryRun)
+	assert.NoErr(t, err)
+	assert.Len(t, res.Copied, 4)
+	assert.False(t, fsutil.PathExists(dst2))
+
+	// skip unchanged
+	res, err = fsutil.CopyDir(src, dst, fsutil.WithCompare(fsutil.CompareHash))
+	assert.NoErr(t, err)
+	assert.Len(t, res.Skipped, 2)
+	assert.Len(t, res.Copied, 2)
+
+	_, err = fsutil.CopyDir(src+"/a.txt", dst)
+	assert.Err(t, err)
+	_, err = fsutil.CopyDir(src+"/not-exist", dst)
+	assert.Err(t, err)
+
+	// copy into itself
+	_, err = fsutil.CopyDir(src, src+"/sub/copy")
+	assert.ErrSubMsg(t, err, "cannot copy a dir into itself")
+	assert.False(t, fsutil.PathExists(src+"/sub/copy"))
+	_, err = fsutil.SyncDir(src, src)
+	assert.ErrSubMsg(t, err, "cannot copy a dir into itself")
+
+	// dst name has the src name as prefix, is allowed
+	_, err = fsutil.CopyDir(src, src+"-bak")
+	assert.NoErr(t, err)
+}
+
+func TestCopyDir_preserve(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+	}
+
+	src := makeCopySrc(t)
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	assert.NoErr(t, os.Chmod(src+"/a.txt", 0600))
+	assert.NoErr(t, os.Chtimes(src+"/a.txt", mtime, mtime))
+	assert.NoErr(t, os.Symlink("a.txt", src+"/link.txt"))
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	_, err := fsutil.CopyDir(src, dst, fsutil.WithPreserve)
+	assert.NoErr(t, err)
+
+	fi, err := os.Stat(dst + "/a.txt")
+	assert.NoErr(t, err)
+	assert.Eq(t, os.FileMode(0600), fi.Mode().Perm())
+	assert.True(t, fi.ModTime().Equal(mtime))
+	assert.True(t, fsutil.IsSymlink(dst+"/link.txt"))
+
+	// follow symlink
+	dst2 := filepath.Join(t.TempDir(), "dst2")
+	_, err = fsutil.CopyDir(src, dst2)
+	assert.NoErr(t, err)
+	assert.False(t, fsutil.IsSymlink(dst2+"/link.txt"))
+	assert.Eq(t, "a", fsutil.ReadString(dst2+"/link.txt"))
+
+	// follow the dir symlink to parent dir
+	assert.NoErr(t, os.Symlink("..", src+"/sub/loop"))
+	_, err = fsutil.CopyDir(src, filepath.Join(t.TempDir(), "dst3"))
+	assert.ErrSubMsg(t, err, "symlink loop detected")
+
+	// keep symlinks, no loop
+	dst4 := filepath.Join(t.TempDir(), "dst4")
+	_, err = fsutil.CopyDir(src, dst4, fsutil.WithPreserve)
+	assert.NoErr(t, err)
+	assert.True(t, fsutil.IsSymlink(dst4+"/sub/loop"))
+}
+
+func TestSyncDir(t *testing.T) {
+	src := makeCopySrc(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	res, err := fsutil.SyncDir(src, dst)
+	assert.NoErr(t, err)
+	assert.Len(t, res.Copied, 4)
+
+	fsutil.Must2(fsutil.PutContents(dst+"/extra.txt", "extra"))
+	fsutil.Must2(fsutil.PutContents(dst+"/old/d.txt", "d"))
+	fsutil.Must2(fsutil.PutContents(dst+"/.keep", "keep"))
+	fsutil.Must2(fsutil.PutContents(src+"/a.txt", "a2"))
+
+	// dry run
+	res, err = fsutil.SyncDir(src, dst, fsutil.WithDryRun, fsutil.WithFilters(fsutil.ExcludeDotFile))
+	assert.NoErr(t, err)
+	assert.Len(t, res.Removed, 2)
+	assert.True(t, fsutil.IsFile(dst+"/extra.txt"))
+
+	res, err = fsutil.SyncDir(src, dst, fsutil.WithFilters(fsutil.ExcludeDotFile))
+	assert.NoErr(t, err)
+	assert.Len(t, res.Copied, 1)
+	assert.Len(t, res.Skipped, 2)
+	assert.Len(t, res.Removed, 2)
+	assert.Eq(t, "a2", fsutil.ReadString(dst+"/a.txt"))
+	assert.False(t, fsutil.PathExists(dst+"/extra.txt"))
+	assert.False(t, fsutil.PathExists(dst+"/old"))
+	// filtered path will be kept
+	assert.True(t, fsutil.IsFile(dst+"/.keep"))
+}
+
+func TestMove(t *testing.T) {
+	src := makeCopySrc(t)
+	dst := filepath.Join(t.TempDir(), "moved")
+
+	assert.NoErr(t, fsutil.Move(src, dst))
+	assert.False(t, fsutil.PathExists(src))
+	assert.Eq(t, "b", fsutil.ReadString(dst+"/sub/b.txt"))
+
+	assert.NoErr(t, fsutil.Move(dst+"/a.txt", dst+"/a2.txt"))
+	assert.Eq(t, "a", fsutil.ReadString(dst+"/a2.txt"))
+	assert.Err(t, fsutil.Move(dst+"/not-exist", dst+"/a3.txt"))
+}
@@ -0,0 +1,235 @@
+package fsutil
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gookit/goutil/x/encodes/hashutil"
+)
+
+// ************************************************************
+//	file content hash, checksum
+// ************************************************************
+
+// FileHash calc the file content hash by streaming read, returns hex string.
+//
+// algo: crc32, crc64, md5, sha1, sha224, sha256, sha384, sha512. see hashutil.NewHash()
+//
+// Usage:
+//
+//	sum, err := fsutil.FileHash("path/to/file.zip", "sha256")
+func FileHash(fPath, algo string) (string, error) {
+	fh, err := os.Open(fPath)
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close()
+
+	return ReaderHash(fh, algo)
+}
+
+// ReaderHash calc the reader content hash, returns hex string.
+func ReaderHash(r io.Reader, algo string) (string, error) {
+	h, err := hashutil.TryNewHash(algo)
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DirHash calc a stable Merkle-style checksum of the dir tree. returns hex string.
+//
+// Each dir hash is calc by the sorted entries "type name hash" lines,
+// so the result only changes when file contents, names or tree structure changed.
+// The mode and mtime are not included.
+//
+// filters: return false will skip the file or dir.
+func DirHash(dir, algo string, filters ...FilterFunc) (string, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", &os.PathError{Op: "dir hash", Path: dir, Err: fmt.Errorf("not a directory")}
+	}
+	if _, err = hashutil.TryNewHash(algo); err != nil {
+		return "", err
+	}
+	return dirHash(dir, algo, filters)
+}
+
+func dirHash(dir, algo string, filters []FilterFunc) (string, error) {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	h := hashutil.NewHash(algo) // algo has been checked in DirHash
+	for _, ent := range des {
+		fPath := filepath.Join(dir, ent.Name())
+		if len(filters) > 0 && ApplyFilters(fPath, ent, filters) {
+			continue
+		}
+
+		var typ, sum string
+		switch {
+		case ent.IsDir():
+			typ = "d"
+			sum, err = dirHash(fPath, algo, filters)
+		case ent.Type()&fs.ModeSymlink != 0:
+			var target string
+			typ = "l"
+			if target, err = os.Readlink(fPath); err == nil {
+				sum = hashutil.Hash(algo, target)
+			}
+		case ent.Type().IsRegular():
+			typ = "f"
+			sum, err = FileHash(fPath, algo)
+		default:
+			continue // skip other types. eg: socket, device
+		}
+
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(h, "%s %q %s\n", typ, ent.Name(), sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// FindDuplicates find the duplicate files in dirs(recursive).
+// will group files by size first, then compare by sha256 hash.
+//
+// Returns the duplicate file groups, each group has two or more paths. empty files are ignored.
+func FindDuplicates(dirs ...string) ([][]string, error) {
+	bySize := make(map[int64][]string)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(fPath string, ent fs.DirEntry, err error) error {
+			if err != nil || !ent.Type().IsRegular() {
+				return err
+			}
+
+			fi, err := ent.Info()
+			if err != nil {
+				return err
+			}
+			if fi.Size() > 0 {
+				bySize[fi.Size()] = append(bySize[fi.Size()], fPath)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var groups [][]string
+	for _, paths := range bySize {
+		if len(paths) < 2 {
+			continue
+		}
+
+		byHash := make(map[string][]string, len(paths))
+		for _, fPath := range paths {
+			sum, err := FileHash(fPath, hashutil.AlgoSHA256)
+			if err != nil {
+				return nil, err
+			}
+			byHash[sum] = append(byHash[sum], fPath)
+		}
+
+		for _, same := range byHash {
+			if len(same) > 1 {
+				sort.Strings(same)
+				groups = append(groups, same)
+			}
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups, nil
+}
+
+// WriteManifest write the checksum manifest of all files in the dir(recursive) to writer.
+// the format is compatible with the `sha256sum` command. eg:
+//
+//	e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  sub/file.txt
+//
+// The file paths are relative to the dir and use slash separator.
+func WriteManifest(w io.Writer, dir, algo string, filters ...FilterFunc) error {
+	return filepath.WalkDir(dir, func(fPath string, ent fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if fPath == dir {
+			return nil
+		}
+
+		if len(filters) > 0 && ApplyFilters(fPath, ent, filters) {
+			if ent.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !ent.Type().IsRegular() {
+			return nil
+		}
+
+		sum, err := FileHash(fPath, algo)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, fPath)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "%s  %s\n", sum, filepath.ToSlash(rel))
+		return err
+	})
+}
+
+// VerifyManifest verify the files in the dir by the checksum manifest. see WriteManifest()
+//
+// Returns the failed file paths(checksum not matched or cannot read),
+// and returns error if the manifest is invalid or has failed files.
+func VerifyManifest(r io.Reader, dir, algo string) (failed []string, err error) {
+	s := bufio.NewScanner(r)
+	for num := 1; s.Scan(); num++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		// format: "HASH  path" or "HASH *path"(binary mode)
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok || len(name) < 2 || (name[0] != ' ' && name[0] != '*') {
+			return failed, fmt.Errorf("invalid manifest line #%d: %s", num, line)
+		}
+
+		name = name[1:]
+		actual, err1 := FileHash(filepath.Join(dir, filepath.FromSlash(name)), algo)
+		if err1 != nil || !strings.EqualFold(actual, sum) {
+			failed = append(failed, name)
+		}
+	}
+
+	if err = s.Err(); err != nil {
+		return failed, err
+	}
+	if len(failed) > 0 {
+		err = fmt.Errorf("%d files checksum verify failed", len(failed))
+	}
+	return failed, err
+}
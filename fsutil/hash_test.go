@@ -0,0 +1,125 @@
+package fsutil_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/assert"
+	"github.com/gookit/goutil/x/encodes/hashutil"
+)
+
+func TestFileHash(t *testing.T) {
+	fPath := filepath.Join(t.TempDir(), "a.txt")
+	fsutil.Must2(fsutil.PutContents(fPath, "hello"))
+
+	sum, err := fsutil.FileHash(fPath, "sha256")
+	assert.NoErr(t, err)
+	assert.Eq(t, hashutil.Hash("sha256", "hello"), sum)
+	assert.Eq(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sum)
+
+	sum, err = fsutil.FileHash(fPath, "md5")
+	assert.NoErr(t, err)
+	assert.Eq(t, hashutil.MD5("hello"), sum)
+
+	_, err = fsutil.FileHash(fPath+".notexist", "md5")
+	assert.Err(t, err)
+
+	// invalid algo
+	_, err = fsutil.FileHash(fPath, "sha3")
+	assert.ErrSubMsg(t, err, "invalid hash algorithm")
+	_, err = fsutil.ReaderHash(strings.NewReader("hello"), "unknown")
+	assert.Err(t, err)
+}
+
+func TestDirHash(t *testing.T) {
+	src := makeCopySrc(t)
+	sum1, err := fsutil.DirHash(src, "sha256")
+	assert.NoErr(t, err)
+	assert.Len(t, sum1, 64)
+
+	// same content in other dir, same hash
+	dst := filepath.Join(t.TempDir(), "dst")
+	_, err = fsutil.CopyDir(src, dst)
+	assert.NoErr(t, err)
+	sum2, err := fsutil.DirHash(dst, "sha256")
+	assert.NoErr(t, err)
+	assert.Eq(t, sum1, sum2)
+
+	_, err = fsutil.DirHash(src, "unknown")
+	assert.ErrSubMsg(t, err, "invalid hash algorithm")
+
+	// with filters
+	sum3, err := fsutil.DirHash(src, "sha256", fsutil.ExcludeDotFile)
+	assert.NoErr(t, err)
+	assert.NotEq(t, sum1, sum3)
+
+	// changed content
+	fsutil.Must2(fsutil.PutContents(dst+"/sub/b.txt", "b2", fsutil.FsCWTFlags))
+	sum2, err = fsutil.DirHash(dst, "sha256")
+	assert.NoErr(t, err)
+	assert.NotEq(t, sum1, sum2)
+
+	// changed on filtered file, hash not changed
+	fsutil.Must2(fsutil.PutContents(src+"/.git/config", "git2", fsutil.FsCWTFlags))
+	sum4, err := fsutil.DirHash(src, "sha256", fsutil.ExcludeDotFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, sum3, sum4)
+
+	_, err = fsutil.DirHash(src+"/a.txt", "sha256")
+	assert.Err(t, err)
+}
+
+func TestFindDuplicates(t *testing.T) {
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	fsutil.Must2(fsutil.PutContents(dir1+"/a.txt", "same"))
+	fsutil.Must2(fsutil.PutContents(dir1+"/sub/b.txt", "same"))
+	fsutil.Must2(fsutil.PutContents(dir1+"/c.txt", "diff")) // same size, diff content
+	fsutil.Must2(fsutil.PutContents(dir2+"/d.txt", "same"))
+	fsutil.Must2(fsutil.PutContents(dir2+"/e.txt", ""))
+	fsutil.Must2(fsutil.PutContents(dir2+"/f.txt", ""))
+
+	groups, err := fsutil.FindDuplicates(dir1, dir2)
+	assert.NoErr(t, err)
+	assert.Len(t, groups, 1)
+	assert.Len(t, groups[0], 3)
+	assert.Contains(t, groups[0], filepath.Join(dir2, "d.txt"))
+	assert.NotContains(t, groups[0], filepath.Join(dir1, "c.txt"))
+}
+
+func TestWriteManifest(t *testing.T) {
+	src := makeCopySrc(t)
+
+	buf := new(bytes.Buffer)
+	err := fsutil.WriteManifest(buf, src, "sha256", fsutil.ExcludeDotFile)
+	assert.NoErr(t, err)
+	content := buf.String()
+	assert.StrContains(t, content, hashutil.Hash("sha256", "a")+"  a.txt\n")
+	assert.StrContains(t, content, "  sub/b.txt\n")
+	assert.StrNotContains(t, content, ".git/config")
+	assert.Len(t, strings.Split(strings.TrimSpace(content), "\n"), 3)
+
+	// verify ok
+	failed, err := fsutil.VerifyManifest(strings.NewReader(content), src, "sha256")
+	assert.NoErr(t, err)
+	assert.Empty(t, failed)
+
+	// binary mode marker and comments
+	bin := "# comment\n" + hashutil.Hash("sha256", "a") + " *a.txt\n"
+	_, err = fsutil.VerifyManifest(strings.NewReader(bin), src, "sha256")
+	assert.NoErr(t, err)
+
+	// verify fail
+	fsutil.Must2(fsutil.PutContents(src+"/sub/b.txt", "changed", fsutil.FsCWTFlags))
+	fsutil.MustRemove(src + "/a.txt")
+	failed, err = fsutil.VerifyManifest(strings.NewReader(content), src, "sha256")
+	assert.Err(t, err)
+	assert.Eq(t, []string{"a.txt", "sub/b.txt"}, failed)
+
+	// invalid line
+	_, err = fsutil.VerifyManifest(strings.NewReader("invalid-line"), src, "sha256")
+	assert.ErrSubMsg(t, err, "invalid manifest line #1")
+}
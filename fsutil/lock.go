@@ -0,0 +1,151 @@
+package fsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ************************************************************
+//	file lock for cross-process
+// ************************************************************
+
+// ErrLocked the file has been locked by other process. on TryLock()
+var ErrLocked = errors.New("fsutil: file is locked by another process")
+
+// UnlockFunc release the file lock
+type UnlockFunc func() error
+
+// LockOption for lock file
+type LockOption struct {
+	// Shared use shared(read) lock, default is exclusive(write) lock.
+	Shared bool
+	// PollInterval for try lock on LockContext(). default is 50ms
+	PollInterval time.Duration
+}
+
+// LockOptionFunc for lock file
+type LockOptionFunc func(opt *LockOption)
+
+// NewLockOption create a new LockOption instance
+func NewLockOption(optFns ...LockOptionFunc) *LockOption {
+	opt := &LockOption{PollInterval: 50 * time.Millisecond}
+	for _, fn := range optFns {
+		fn(opt)
+	}
+	return opt
+}
+
+// WithShared use shared lock, multi processes can hold the shared lock at same time.
+func WithShared(opt *LockOption) {
+	opt.Shared = true
+}
+
+// WithPollInterval set poll interval for LockContext()
+func WithPollInterval(interval time.Duration) LockOptionFunc {
+	return func(opt *LockOption) {
+		opt.PollInterval = interval
+	}
+}
+
+// Lock the file, will block until the lock is acquired. will auto create the file.
+//
+// On exclusive lock, will write current PID to the lock file. so the file
+// is compatible with process.PidFile, can check the lock holder by LockHolder().
+//
+// Usage:
+//
+//	unlock, err := fsutil.Lock("path/to/app.lock")
+//	if err != nil {
+//		return err
+//	}
+//	defer unlock()
+func Lock(fPath string, optFns ...LockOptionFunc) (UnlockFunc, error) {
+	return acquireLock(fPath, NewLockOption(optFns...), true)
+}
+
+// TryLock try to lock the file, will return ErrLocked if it has been locked by other process.
+func TryLock(fPath string, optFns ...LockOptionFunc) (UnlockFunc, error) {
+	return acquireLock(fPath, NewLockOption(optFns...), false)
+}
+
+// LockContext lock the file, will try lock until the lock is acquired or the context is done.
+func LockContext(ctx context.Context, fPath string, optFns ...LockOptionFunc) (UnlockFunc, error) {
+	opt := NewLockOption(optFns...)
+	ticker := time.NewTicker(opt.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		unlock, err := acquireLock(fPath, opt, false)
+		if !errors.Is(err, ErrLocked) {
+			return unlock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// LockHolder get the PID of the exclusive lock holder from lock file.
+// returns 0 if the file not exists or not locked.
+func LockHolder(fPath string) int {
+	bs, err := os.ReadFile(fPath)
+	if err != nil {
+		return 0
+	}
+
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(bs)))
+	return pid
+}
+
+func acquireLock(fPath string, opt *LockOption, block bool) (UnlockFunc, error) {
+	fh, err := OpenFile(fPath, os.O_CREATE|os.O_RDWR, DefaultFilePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = lockFile(fh, opt.Shared, block); err != nil {
+		_ = fh.Close()
+		return nil, err
+	}
+
+	if !opt.Shared {
+		if err = writeLockPID(fh, os.Getpid()); err != nil {
+			_ = unlockFile(fh)
+			_ = fh.Close()
+			return nil, err
+		}
+	}
+
+	var unlocked bool
+	return func() error {
+		if unlocked {
+			return nil
+		}
+		unlocked = true
+
+		// clear the PID before release the lock
+		if !opt.Shared {
+			_ = fh.Truncate(0)
+		}
+		err := unlockFile(fh)
+		if err1 := fh.Close(); err1 != nil && err == nil {
+			err = err1
+		}
+		return err
+	}, nil
+}
+
+func writeLockPID(fh *os.File, pid int) error {
+	if err := fh.Truncate(0); err != nil {
+		return err
+	}
+	_, err := fh.WriteAt([]byte(strconv.Itoa(pid)), 0)
+	return err
+}
@@ -0,0 +1,19 @@
+//go:build !windows && !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package fsutil
+
+import (
+	"errors"
+	"os"
+)
+
+// errLockNotSupported error for lock file on not supported OS. eg: js/wasm, plan9, solaris
+var errLockNotSupported = errors.New("fsutil: file lock is not supported on the OS")
+
+func lockFile(_ *os.File, _, _ bool) error {
+	return errLockNotSupported
+}
+
+func unlockFile(_ *os.File) error {
+	return errLockNotSupported
+}
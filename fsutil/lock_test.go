@@ -0,0 +1,62 @@
+package fsutil_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestLock(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), "sub/test.lock")
+
+	unlock, err := fsutil.Lock(lockFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, os.Getpid(), fsutil.LockHolder(lockFile))
+
+	_, err = fsutil.TryLock(lockFile)
+	assert.ErrIs(t, err, fsutil.ErrLocked)
+	_, err = fsutil.TryLock(lockFile, fsutil.WithShared)
+	assert.ErrIs(t, err, fsutil.ErrLocked)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 80*time.Millisecond)
+	defer cancel()
+	_, err = fsutil.LockContext(ctx, lockFile, fsutil.WithPollInterval(10*time.Millisecond))
+	assert.ErrIs(t, err, context.DeadlineExceeded)
+
+	// release on other goroutine
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = unlock()
+	}()
+
+	unlock2, err := fsutil.LockContext(context.Background(), lockFile, fsutil.WithPollInterval(10*time.Millisecond))
+	assert.NoErr(t, err)
+	assert.NoErr(t, unlock2())
+	assert.NoErr(t, unlock2()) // repeat unlock
+	assert.Eq(t, 0, fsutil.LockHolder(lockFile))
+}
+
+func TestLock_shared(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), "shared.lock")
+
+	unlock1, err := fsutil.TryLock(lockFile, fsutil.WithShared)
+	assert.NoErr(t, err)
+	unlock2, err := fsutil.TryLock(lockFile, fsutil.WithShared)
+	assert.NoErr(t, err)
+
+	_, err = fsutil.TryLock(lockFile)
+	assert.ErrIs(t, err, fsutil.ErrLocked)
+
+	assert.NoErr(t, unlock1())
+	assert.NoErr(t, unlock2())
+
+	unlock, err := fsutil.TryLock(lockFile)
+	assert.NoErr(t, err)
+	assert.NoErr(t, unlock())
+	assert.Eq(t, 0, fsutil.LockHolder(filepath.Join(t.TempDir(), "not-exist.lock")))
+}
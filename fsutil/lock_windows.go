@@ -0,0 +1,32 @@
+package fsutil
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile lock the file by LockFileEx
+func lockFile(fh *os.File, shared, block bool) error {
+	var flags uint32
+	if !shared {
+		flags |= windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	if !block {
+		flags |= windows.LOCKFILE_FAIL_IMMEDIATELY
+	}
+
+	// lock the region beyond the file contents, so other process can still read the PID.
+	ol := &windows.Overlapped{OffsetHigh: 1}
+	err := windows.LockFileEx(windows.Handle(fh.Fd()), flags, 0, 1, 0, ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return ErrLocked
+	}
+	return err
+}
+
+func unlockFile(fh *os.File) error {
+	ol := &windows.Overlapped{OffsetHigh: 1}
+	return windows.UnlockFileEx(windows.Handle(fh.Fd()), 0, 1, 0, ol)
+}
@@ -0,0 +1,319 @@
+package fsutil
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// some errors for MemFS
+var (
+	errNotDir   = errors.New("not a directory")
+	errIsDir    = errors.New("is a directory")
+	errNotEmpty = errors.New("directory not empty")
+)
+
+// memNode a file or dir in the MemFS
+type memNode struct {
+	name  string
+	data  []byte
+	mode  fs.FileMode
+	mtime time.Time
+}
+
+func (n *memNode) info() *memInfo {
+	return &memInfo{name: n.name, size: int64(len(n.data)), mode: n.mode, mtime: n.mtime}
+}
+
+// memInfo implements fs.FileInfo
+type memInfo struct {
+	name  string
+	size  int64
+	mode  fs.FileMode
+	mtime time.Time
+}
+
+func (fi *memInfo) Name() string       { return fi.name }
+func (fi *memInfo) Size() int64        { return fi.size }
+func (fi *memInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi *memInfo) ModTime() time.Time { return fi.mtime }
+func (fi *memInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *memInfo) Sys() any           { return nil }
+
+// MemFS an in-memory filesystem implements the FS interface. it is safe for concurrent use.
+//
+// The names must be valid fs.FS paths. eg: "path/to/file.txt"
+//
+// Usage:
+//
+//	mfs := fsutil.NewMemFS()
+//	err := fsutil.WriteFileFS(mfs, "config/app.yaml", "name: app", 0644)
+type MemFS struct {
+	mu    sync.RWMutex
+	nodes map[string]*memNode
+}
+
+// NewMemFS create a new in-memory filesystem
+func NewMemFS() *MemFS {
+	return &MemFS{
+		nodes: map[string]*memNode{
+			".": {name: ".", mode: fs.ModeDir | 0755, mtime: time.Now()},
+		},
+	}
+}
+
+func (m *MemFS) check(op, name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return nil
+}
+
+// lookup the node by name. must be called with lock
+func (m *MemFS) lookup(op, name string) (*memNode, error) {
+	if err := m.check(op, name); err != nil {
+		return nil, err
+	}
+
+	node, ok := m.nodes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return node, nil
+}
+
+// Open the named file for reading
+func (m *MemFS) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	mf := &memFile{path: name, info: node.info()}
+	if node.mode.IsDir() {
+		mf.entries = m.readDir(name)
+	} else {
+		mf.reader = bytes.NewReader(node.data)
+	}
+	return mf, nil
+}
+
+// Stat returns a FileInfo describing the named file
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, err := m.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return node.info(), nil
+}
+
+// ReadFile reads the named file and returns a copy of its contents
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, err := m.lookup("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errIsDir}
+	}
+	return append([]byte(nil), node.data...), nil
+}
+
+// ReadDir reads the named directory, returns all its directory entries sorted by filename.
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, err := m.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+	return m.readDir(name), nil
+}
+
+// readDir returns the sorted children of the dir. must be called with lock
+func (m *MemFS) readDir(dir string) []fs.DirEntry {
+	var ents []fs.DirEntry
+	for p, node := range m.nodes {
+		if p != "." && path.Dir(p) == dir {
+			ents = append(ents, fs.FileInfoToDirEntry(node.info()))
+		}
+	}
+
+	sort.Slice(ents, func(i, j int) bool { return ents[i].Name() < ents[j].Name() })
+	return ents
+}
+
+// WriteFile writes data to the named file, creating it if necessary.
+// the parent dir must exist.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := m.check("write", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if parent, ok := m.nodes[path.Dir(name)]; !ok {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrNotExist}
+	} else if !parent.mode.IsDir() {
+		return &fs.PathError{Op: "write", Path: name, Err: errNotDir}
+	}
+
+	if node, ok := m.nodes[name]; ok {
+		if node.mode.IsDir() {
+			return &fs.PathError{Op: "write", Path: name, Err: errIsDir}
+		}
+		// keep the perm of exists file, like os.WriteFile
+		perm = node.mode.Perm()
+	}
+
+	m.nodes[name] = &memNode{
+		name:  path.Base(name),
+		data:  append([]byte(nil), data...),
+		mode:  perm.Perm(),
+		mtime: time.Now(),
+	}
+	return nil
+}
+
+// MkdirAll creates a directory named path, along with any necessary parents.
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	if err := m.check("mkdir", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var dir string
+	for _, elem := range strings.Split(name, "/") {
+		dir = path.Join(dir, elem)
+		if node, ok := m.nodes[dir]; ok {
+			if !node.mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: dir, Err: errNotDir}
+			}
+			continue
+		}
+
+		m.nodes[dir] = &memNode{name: elem, mode: fs.ModeDir | perm.Perm(), mtime: time.Now()}
+	}
+	return nil
+}
+
+// Remove removes the named file or (empty) directory.
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, err := m.lookup("remove", name)
+	if err != nil {
+		return err
+	}
+	if name == "." || node.mode.IsDir() && len(m.readDir(name)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+
+	delete(m.nodes, name)
+	return nil
+}
+
+// RemoveAll removes path and any children it contains.
+// returns nil if the path does not exist.
+func (m *MemFS) RemoveAll(name string) error {
+	if err := m.check("remove", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for p := range m.nodes {
+		if p == "." {
+			continue
+		}
+		if name == "." || p == name || strings.HasPrefix(p, name+"/") {
+			delete(m.nodes, p)
+		}
+	}
+	return nil
+}
+
+// memFile implements fs.File and fs.ReadDirFile for MemFS
+type memFile struct {
+	path   string
+	info   *memInfo
+	reader *bytes.Reader
+	// for dir
+	entries []fs.DirEntry
+	offset  int
+	closed  bool
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.path, Err: fs.ErrClosed}
+	}
+	if f.reader == nil {
+		return 0, &fs.PathError{Op: "read", Path: f.path, Err: errIsDir}
+	}
+	return f.reader.Read(p)
+}
+
+// Seek implements io.Seeker
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	if f.reader == nil {
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: errIsDir}
+	}
+	return f.reader.Seek(offset, whence)
+}
+
+// ReadDir implements fs.ReadDirFile
+func (f *memFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if f.reader != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: f.path, Err: errNotDir}
+	}
+
+	remain := f.entries[f.offset:]
+	if n <= 0 {
+		f.offset = len(f.entries)
+		return remain, nil
+	}
+
+	if len(remain) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remain) {
+		n = len(remain)
+	}
+	f.offset += n
+	return remain[:n], nil
+}
+
+func (f *memFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.path, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
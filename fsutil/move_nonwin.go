@@ -0,0 +1,13 @@
+//go:build !windows && !plan9
+
+package fsutil
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDevice check the rename error is cross device(EXDEV)
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,4 @@
+package fsutil
+
+// isCrossDevice on plan9 is not supported, will not fallback to copy.
+func isCrossDevice(_ error) bool { return false }
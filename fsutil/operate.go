@@ -286,6 +286,8 @@ func RemoveSub(dirPath string, fns ...FilterFunc) error {
 
 // Unzip a zip archive
 // from https://blog.csdn.net/wangshubo1989/article/details/71743374
+//
+// TIP: more archive functions please see the package fsutil/archive
 func Unzip(archive, targetDir string) (err error) {
 	reader, err := zip.OpenReader(archive)
 	if err != nil {
@@ -0,0 +1,384 @@
+package fsutil
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gookit/goutil/timex"
+)
+
+// ************************************************************
+//	rotate files and retention
+// ************************************************************
+
+// PrunePolicy for Prune files in a dir. all non-zero rules will be applied.
+type PrunePolicy struct {
+	// MaxAge remove files that modified before now - MaxAge.
+	MaxAge time.Duration
+	// MaxCount keep the newest N files.
+	MaxCount int
+	// MaxTotalSize keep the newest files which total size <= MaxTotalSize.
+	MaxTotalSize int64
+	// Filters only handle the files matched filters. return false will skip the file.
+	Filters []FilterFunc
+}
+
+type pruneFile struct {
+	path string
+	size int64
+	mt   time.Time
+}
+
+// Prune remove files in the dir(not recursive) by age, count or total size.
+// the newer files(by modify time) are kept first.
+//
+// Returns the removed file paths.
+//
+// Usage:
+//
+//	// keep the newest 10 logs, and remove logs older than 7 days
+//	removed, err := fsutil.Prune("/path/to/logs", fsutil.PrunePolicy{
+//		MaxAge:   7 * 24 * time.Hour,
+//		MaxCount: 10,
+//		Filters:  []fsutil.FilterFunc{fsutil.IncludeSuffix(".log", ".log.gz")},
+//	})
+func Prune(dir string, policy PrunePolicy) ([]string, error) {
+	var files []pruneFile
+	filters := append([]FilterFunc{OnlyFindFile}, policy.Filters...)
+
+	err := FindInDir(dir, func(fPath string, ent fs.DirEntry) error {
+		fi, err := ent.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, pruneFile{path: fPath, size: fi.Size(), mt: fi.ModTime()})
+		return nil
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	// newest first
+	sort.SliceStable(files, func(i, j int) bool { return files[i].mt.After(files[j].mt) })
+
+	var total int64
+	var removed []string
+	expireAt := time.Now().Add(-policy.MaxAge)
+
+	for i, f := range files {
+		total += f.size
+		if (policy.MaxAge > 0 && f.mt.Before(expireAt)) ||
+			(policy.MaxCount > 0 && i >= policy.MaxCount) ||
+			(policy.MaxTotalSize > 0 && total > policy.MaxTotalSize) {
+			if err := os.Remove(f.path); err != nil {
+				return removed, err
+			}
+			removed = append(removed, f.path)
+		}
+	}
+	return removed, nil
+}
+
+// RotateOption for RotateWriter
+type RotateOption struct {
+	// MaxSize rotate the file when size exceeds MaxSize. 0 for disable.
+	MaxSize int64
+	// Interval rotate the file by time interval. eg: time.Hour, 24*time.Hour. 0 for disable.
+	//
+	// NOTE: the time is aligned by UTC. eg: 24*time.Hour will rotate at UTC 00:00
+	Interval time.Duration
+	// MaxBackups keep the newest N backup files. 0 for keep all.
+	MaxBackups int
+	// MaxAge remove backup files older than MaxAge. 0 for disable.
+	MaxAge time.Duration
+	// Compress backup files by gzip. the backup file will add suffix ".gz"
+	Compress bool
+	// TimeTpl timex date template for backup file name. default: "Ymd_HIS"
+	//
+	// eg: "app.log" will rotate to "app.20240102_150405.log"
+	TimeTpl string
+	// FilePerm for create new file. default is DefaultFilePerm
+	FilePerm os.FileMode
+}
+
+// RotateOptionFunc for RotateWriter
+type RotateOptionFunc func(opt *RotateOption)
+
+// WithMaxSize set rotate by file size
+func WithMaxSize(size int64) RotateOptionFunc {
+	return func(opt *RotateOption) { opt.MaxSize = size }
+}
+
+// WithInterval set rotate by time interval
+func WithInterval(interval time.Duration) RotateOptionFunc {
+	return func(opt *RotateOption) { opt.Interval = interval }
+}
+
+// WithMaxBackups set max backup files number to keep
+func WithMaxBackups(n int) RotateOptionFunc {
+	return func(opt *RotateOption) { opt.MaxBackups = n }
+}
+
+// WithCompress compress backup files by gzip
+func WithCompress(opt *RotateOption) { opt.Compress = true }
+
+// RotateWriter an io.WriteCloser that rotate the file by size or time, and keep N backups.
+//
+// Usage:
+//
+//	w, err := fsutil.NewRotateWriter("/path/to/app.log", fsutil.WithMaxSize(10*1024*1024), fsutil.WithMaxBackups(5))
+//	if err != nil {
+//		return err
+//	}
+//	defer w.Close()
+//
+//	log.SetOutput(w)
+type RotateWriter struct {
+	mu   sync.Mutex
+	opt  *RotateOption
+	path string
+	file *os.File
+	size int64
+	// next rotate time. only for Interval > 0
+	nextAt time.Time
+}
+
+// NewRotateWriter create a new RotateWriter, will open the file for append write.
+func NewRotateWriter(fPath string, optFns ...RotateOptionFunc) (*RotateWriter, error) {
+	opt := &RotateOption{
+		TimeTpl:  "Ymd_HIS",
+		FilePerm: DefaultFilePerm,
+	}
+	for _, fn := range optFns {
+		fn(opt)
+	}
+
+	rw := &RotateWriter{opt: opt, path: fPath}
+	if err := rw.open(); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+// Path get the file path
+func (rw *RotateWriter) Path() string { return rw.path }
+
+// Write data to file, will rotate the file before write if need.
+func (rw *RotateWriter) Write(p []byte) (n int, err error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if rw.needRotate(int64(len(p))) {
+		if err = rw.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = rw.file.Write(p)
+	rw.size += int64(n)
+	return n, err
+}
+
+// WriteString to file. see Write()
+func (rw *RotateWriter) WriteString(s string) (int, error) {
+	return rw.Write([]byte(s))
+}
+
+// Rotate the file manually.
+func (rw *RotateWriter) Rotate() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.file == nil {
+		return os.ErrClosed
+	}
+	return rw.rotate()
+}
+
+// Sync the file contents to disk
+func (rw *RotateWriter) Sync() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.file == nil {
+		return os.ErrClosed
+	}
+	return rw.file.Sync()
+}
+
+// Close the file
+func (rw *RotateWriter) Close() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.file == nil {
+		return nil
+	}
+
+	err := rw.file.Close()
+	rw.file = nil
+	return err
+}
+
+func (rw *RotateWriter) open() error {
+	file, err := OpenAppendFile(rw.path, rw.opt.FilePerm)
+	if err != nil {
+		return err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	rw.file = file
+	rw.size = fi.Size()
+	if rw.opt.Interval > 0 {
+		rw.nextAt = time.Now().Truncate(rw.opt.Interval).Add(rw.opt.Interval)
+	}
+	return nil
+}
+
+func (rw *RotateWriter) needRotate(n int64) bool {
+	if rw.opt.MaxSize > 0 && rw.size > 0 && rw.size+n > rw.opt.MaxSize {
+		return true
+	}
+	if rw.opt.Interval <= 0 || time.Now().Before(rw.nextAt) {
+		return false
+	}
+
+	// skip rotate empty file, just move to next period
+	if rw.size == 0 {
+		rw.nextAt = time.Now().Truncate(rw.opt.Interval).Add(rw.opt.Interval)
+		return false
+	}
+	return true
+}
+
+func (rw *RotateWriter) rotate() error {
+	if err := rw.file.Close(); err != nil {
+		return err
+	}
+	rw.file = nil
+
+	backup := rw.backupPath(time.Now())
+	if err := os.Rename(rw.path, backup); err != nil {
+		_ = rw.open() // keep the writer available
+		return err
+	}
+	if err := rw.open(); err != nil {
+		return err
+	}
+
+	if rw.opt.Compress {
+		if err := gzipFile(backup); err != nil {
+			return err
+		}
+	}
+
+	if rw.opt.MaxBackups > 0 || rw.opt.MaxAge > 0 {
+		_, err := Prune(filepath.Dir(rw.path), PrunePolicy{
+			MaxAge:   rw.opt.MaxAge,
+			MaxCount: rw.opt.MaxBackups,
+			Filters:  []FilterFunc{rw.isBackup},
+		})
+		return err
+	}
+	return nil
+}
+
+// backup file path. eg: "app.log" => "app.20240102_150405.log"
+func (rw *RotateWriter) backupPath(now time.Time) string {
+	ext := filepath.Ext(rw.path)
+	prefix := rw.path[:len(rw.path)-len(ext)] + "." + timex.FormatByTpl(now, rw.opt.TimeTpl)
+
+	backup := prefix + ext
+	for i := 1; PathExists(backup) || PathExists(backup+".gz"); i++ {
+		backup = prefix + "." + strconv.Itoa(i) + ext
+	}
+	return backup
+}
+
+// isBackup check the file name is created by backupPath(). eg: "app.20240102_150405.log", "app.20240102_150405.1.log.gz"
+func (rw *RotateWriter) isBackup(_ string, ent fs.DirEntry) bool {
+	base := filepath.Base(rw.path)
+	ext := filepath.Ext(base)
+	prefix := base[:len(base)-len(ext)] + "."
+
+	name := strings.TrimSuffix(ent.Name(), ".gz")
+	if len(name) <= len(prefix)+len(ext) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+		return false
+	}
+
+	// the middle part: "TIME" or "TIME.N"
+	layout := timex.ToLayout(rw.opt.TimeTpl)
+	mid := name[len(prefix) : len(name)-len(ext)]
+	if _, err := time.Parse(layout, mid); err == nil {
+		return true
+	}
+
+	idx := strings.LastIndexByte(mid, '.')
+	if idx < 0 {
+		return false
+	}
+	if _, err := strconv.Atoi(mid[idx+1:]); err != nil {
+		return false
+	}
+	_, err := time.Parse(layout, mid[:idx])
+	return err == nil
+}
+
+// gzip the file to "file.gz", keep the modify time and remove the source file.
+func gzipFile(fPath string) error {
+	src, err := os.Open(fPath)
+	if err != nil {
+		return err
+	}
+
+	fi, err := src.Stat()
+	if err != nil {
+		_ = src.Close()
+		return err
+	}
+
+	gzPath := fPath + ".gz"
+	dst, err := os.OpenFile(gzPath, FsCWTFlags, fi.Mode().Perm())
+	if err != nil {
+		_ = src.Close()
+		return err
+	}
+
+	gw := gzip.NewWriter(dst)
+	_, err = io.Copy(gw, src)
+	if err1 := gw.Close(); err == nil {
+		err = err1
+	}
+	if err1 := dst.Close(); err == nil {
+		err = err1
+	}
+	_ = src.Close()
+
+	if err != nil {
+		_ = os.Remove(gzPath)
+		return errors.New("gzip rotated file error: " + err.Error())
+	}
+
+	if err = os.Chtimes(gzPath, fi.ModTime(), fi.ModTime()); err != nil {
+		return err
+	}
+	return os.Remove(fPath)
+}
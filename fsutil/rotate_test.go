@@ -0,0 +1,135 @@
+package fsutil_test
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"a.log", "b.log", "c.log", "d.log", "e.txt"} {
+		fPath := filepath.Join(dir, name)
+		fsutil.Must2(fsutil.PutContents(fPath, strings.Repeat("x", 10)))
+		// a.log is the newest, d.log is the oldest
+		mt := now.Add(-time.Duration(i) * time.Hour)
+		assert.NoErr(t, os.Chtimes(fPath, mt, mt))
+	}
+	logFilter := []fsutil.FilterFunc{fsutil.IncludeSuffix(".log")}
+
+	// by age
+	removed, err := fsutil.Prune(dir, fsutil.PrunePolicy{MaxAge: 150 * time.Minute, Filters: logFilter})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{dir + "/d.log"}, removed)
+
+	// by total size
+	removed, err = fsutil.Prune(dir, fsutil.PrunePolicy{MaxTotalSize: 25, Filters: logFilter})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{dir + "/c.log"}, removed)
+
+	// by count, without filters
+	removed, err = fsutil.Prune(dir, fsutil.PrunePolicy{MaxCount: 1})
+	assert.NoErr(t, err)
+	assert.Len(t, removed, 2)
+	assert.True(t, fsutil.IsFile(dir+"/a.log"))
+	assert.False(t, fsutil.IsFile(dir+"/e.txt"))
+}
+
+func TestRotateWriter_size(t *testing.T) {
+	dir := t.TempDir()
+	fPath := filepath.Join(dir, "app.log")
+
+	w, err := fsutil.NewRotateWriter(fPath, fsutil.WithMaxSize(10), fsutil.WithMaxBackups(2))
+	assert.NoErr(t, err)
+	assert.Eq(t, fPath, w.Path())
+
+	for i := 0; i < 4; i++ {
+		_, err = w.WriteString("12345678\n")
+		assert.NoErr(t, err)
+	}
+	assert.NoErr(t, w.Sync())
+	assert.NoErr(t, w.Close())
+	assert.NoErr(t, w.Close())
+
+	_, err = w.WriteString("closed")
+	assert.Err(t, err)
+
+	files := fsutil.Glob(dir + "/app.*.log")
+	assert.Len(t, files, 2)
+	assert.Eq(t, "12345678\n", fsutil.ReadString(fPath))
+}
+
+func TestRotateWriter_pruneOthers(t *testing.T) {
+	dir := t.TempDir()
+	fPath := filepath.Join(dir, "app.log")
+	// other files with same prefix should not be pruned
+	fsutil.Must2(fsutil.PutContents(dir+"/app.error.log", "error"))
+	fsutil.Must2(fsutil.PutContents(dir+"/app.20240102.log", "other"))
+	fsutil.Must2(fsutil.PutContents(dir+"/app.20240102_150405.x.log", "other"))
+
+	w, err := fsutil.NewRotateWriter(fPath, fsutil.WithMaxSize(10), fsutil.WithMaxBackups(1))
+	assert.NoErr(t, err)
+	for i := 0; i < 4; i++ {
+		_, err = w.WriteString("12345678\n")
+		assert.NoErr(t, err)
+	}
+	assert.NoErr(t, w.Close())
+
+	assert.Eq(t, "error", fsutil.ReadString(dir+"/app.error.log"))
+	assert.True(t, fsutil.IsFile(dir+"/app.20240102.log"))
+	assert.True(t, fsutil.IsFile(dir+"/app.20240102_150405.x.log"))
+	assert.Len(t, fsutil.Glob(dir+"/app.*.log"), 4)
+}
+
+func TestRotateWriter_compress(t *testing.T) {
+	dir := t.TempDir()
+	fPath := filepath.Join(dir, "app.log")
+
+	w, err := fsutil.NewRotateWriter(fPath, fsutil.WithCompress, func(opt *fsutil.RotateOption) {
+		opt.TimeTpl = "Ymd"
+	})
+	assert.NoErr(t, err)
+	defer w.Close()
+
+	_, err = w.WriteString("hello")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Rotate())
+	_, err = w.WriteString("world")
+	assert.NoErr(t, err)
+
+	files := fsutil.Glob(dir + "/app.*.log.gz")
+	assert.Len(t, files, 1)
+	assert.Eq(t, "world", fsutil.ReadString(fPath))
+
+	fh, err := os.Open(files[0])
+	assert.NoErr(t, err)
+	defer fh.Close()
+	gr, err := gzip.NewReader(fh)
+	assert.NoErr(t, err)
+	assert.Eq(t, "hello", fsutil.ReadString(gr))
+}
+
+func TestRotateWriter_interval(t *testing.T) {
+	dir := t.TempDir()
+	fPath := filepath.Join(dir, "app.log")
+
+	w, err := fsutil.NewRotateWriter(fPath, fsutil.WithInterval(50*time.Millisecond))
+	assert.NoErr(t, err)
+	defer w.Close()
+
+	_, err = w.WriteString("first")
+	assert.NoErr(t, err)
+	time.Sleep(60 * time.Millisecond)
+	_, err = w.WriteString("second")
+	assert.NoErr(t, err)
+
+	assert.Len(t, fsutil.Glob(dir+"/app.*.log"), 1)
+	assert.Eq(t, "second", fsutil.ReadString(fPath))
+}
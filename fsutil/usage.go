@@ -0,0 +1,284 @@
+package fsutil
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gookit/goutil/mathutil"
+)
+
+// DiskNode struct. a file or dir node of the disk usage tree
+type DiskNode struct {
+	Name string
+	Path string
+	// Size of the file. for dir is the total size of all sub files.
+	Size  int64
+	IsDir bool
+	// Dirs and Files the number of all sub dirs and files. only for dir
+	Dirs, Files int
+	// Children sub nodes. only for dir and within the depth limit
+	Children []*DiskNode
+}
+
+// String get size and path info
+func (n *DiskNode) String() string {
+	return mathutil.DataSize(uint64(n.Size)) + "\t" + n.Path
+}
+
+// DirSize get the total size of all files in the dir(recursive). will not follow symlinks.
+func DirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, ent fs.DirEntry, err error) error {
+		if err != nil || ent.IsDir() {
+			return err
+		}
+
+		fi, err := ent.Info()
+		if err == nil {
+			size += fi.Size()
+		}
+		return err
+	})
+	return size, err
+}
+
+// DiskUsage scan the dir and returns a tree of sizes, like the `du` command.
+// children are sorted by size desc.
+//
+// depth: the max depth of children to keep, <= 0 for no limit. sizes are always calc on whole tree.
+//
+// Usage:
+//
+//	root, err := fsutil.DiskUsage("/path/to/dir", 1)
+//	for _, sub := range root.Children {
+//		fmt.Println(sub) // eg: "1.20M	/path/to/dir/sub"
+//	}
+func DiskUsage(dir string, depth int, filters ...FilterFunc) (*DiskNode, error) {
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &diskScanner{maxDepth: depth, filters: filters}
+	node, err := ds.scan(dir, fi, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sortNodes(node, func(a, b *DiskNode) bool { return a.Size > b.Size })
+	return node, nil
+}
+
+// TopNFiles find the top N largest files in the dir(recursive). returns sorted by size desc.
+func TopNFiles(dir string, n int, filters ...FilterFunc) ([]*DiskNode, error) {
+	var files []*DiskNode
+	err := filepath.WalkDir(dir, func(fPath string, ent fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fPath != dir && len(filters) > 0 && ApplyFilters(fPath, ent, filters) {
+			if ent.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if ent.IsDir() {
+			return nil
+		}
+
+		fi, err := ent.Info()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, &DiskNode{Name: ent.Name(), Path: fPath, Size: fi.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+	if n > 0 && len(files) > n {
+		files = files[:n]
+	}
+	return files, nil
+}
+
+type diskScanner struct {
+	maxDepth int
+	filters  []FilterFunc
+}
+
+func (ds *diskScanner) scan(fPath string, fi fs.FileInfo, depth int) (*DiskNode, error) {
+	node := &DiskNode{Name: fi.Name(), Path: fPath, IsDir: fi.IsDir()}
+	if !node.IsDir {
+		node.Size = fi.Size()
+		return node, nil
+	}
+
+	des, err := os.ReadDir(fPath)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := ds.maxDepth <= 0 || depth < ds.maxDepth
+	for _, ent := range des {
+		subPath := filepath.Join(fPath, ent.Name())
+		if len(ds.filters) > 0 && ApplyFilters(subPath, ent, ds.filters) {
+			continue
+		}
+
+		subFi, err := ent.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		sub, err := ds.scan(subPath, subFi, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Size += sub.Size
+		if sub.IsDir {
+			node.Dirs += sub.Dirs + 1
+			node.Files += sub.Files
+		} else {
+			node.Files++
+		}
+
+		if keep {
+			node.Children = append(node.Children, sub)
+		}
+	}
+	return node, nil
+}
+
+func sortNodes(node *DiskNode, less func(a, b *DiskNode) bool) {
+	sort.SliceStable(node.Children, func(i, j int) bool {
+		return less(node.Children[i], node.Children[j])
+	})
+	for _, sub := range node.Children {
+		sortNodes(sub, less)
+	}
+}
+
+// TreeOption struct
+type TreeOption struct {
+	// MaxDepth limit the depth of tree, <= 0 for no limit.
+	MaxDepth int
+	// DirsFirst list dirs before files. default: true
+	DirsFirst bool
+	// ShowSize show the size column. dir size is the total size of all sub files.
+	ShowSize bool
+	// Filters for files and dirs. return false will skip it.
+	Filters []FilterFunc
+}
+
+// TreeOptionFunc type
+type TreeOptionFunc func(opt *TreeOption)
+
+// WithMaxDepth set the max depth for Tree
+func WithMaxDepth(depth int) TreeOptionFunc {
+	return func(opt *TreeOption) { opt.MaxDepth = depth }
+}
+
+// WithSizes show the size column for Tree
+func WithSizes(opt *TreeOption) { opt.ShowSize = true }
+
+// WithTreeFilters set filters for Tree
+func WithTreeFilters(fns ...FilterFunc) TreeOptionFunc {
+	return func(opt *TreeOption) { opt.Filters = append(opt.Filters, fns...) }
+}
+
+// Tree render the dir tree like the `tree` command. see WriteTree()
+//
+// Output eg:
+//
+//	testdata
+//	├── sub
+//	│   └── b.txt
+//	└── a.txt
+//
+//	1 directories, 2 files
+func Tree(dir string, optFns ...TreeOptionFunc) (string, error) {
+	var sb strings.Builder
+	err := WriteTree(&sb, dir, optFns...)
+	return sb.String(), err
+}
+
+// WriteTree render the dir tree like the `tree` command, and write to w.
+func WriteTree(w io.Writer, dir string, optFns ...TreeOptionFunc) error {
+	opt := &TreeOption{DirsFirst: true}
+	for _, fn := range optFns {
+		fn(opt)
+	}
+
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+
+	ds := &diskScanner{maxDepth: opt.MaxDepth, filters: opt.Filters}
+	root, err := ds.scan(dir, fi, 0)
+	if err != nil {
+		return err
+	}
+
+	sortNodes(root, func(a, b *DiskNode) bool {
+		if opt.DirsFirst && a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		return a.Name < b.Name
+	})
+
+	tw := &treeWriter{w: w, opt: opt}
+	tw.line("", root.Path, root)
+	tw.children("", root)
+	if tw.err == nil {
+		_, tw.err = fmt.Fprintf(tw.w, "\n%d directories, %d files\n", tw.dirs, tw.files)
+	}
+	return tw.err
+}
+
+type treeWriter struct {
+	w   io.Writer
+	err error
+	opt *TreeOption
+	// displayed dirs and files count
+	dirs, files int
+}
+
+func (tw *treeWriter) children(prefix string, node *DiskNode) {
+	for i, sub := range node.Children {
+		branch, indent := "├── ", "│   "
+		if i == len(node.Children)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		tw.line(prefix+branch, sub.Name, sub)
+		if sub.IsDir {
+			tw.dirs++
+			tw.children(prefix+indent, sub)
+		} else {
+			tw.files++
+		}
+	}
+}
+
+func (tw *treeWriter) line(prefix, name string, node *DiskNode) {
+	if tw.err != nil {
+		return
+	}
+
+	if tw.opt.ShowSize {
+		prefix += fmt.Sprintf("[%8s]  ", mathutil.DataSize(uint64(node.Size)))
+	}
+	_, tw.err = fmt.Fprintln(tw.w, prefix+name)
+}
@@ -0,0 +1,98 @@
+package fsutil_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func makeUsageDir(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "root")
+	fsutil.Must2(fsutil.PutContents(dir+"/a.txt", strings.Repeat("a", 10)))
+	fsutil.Must2(fsutil.PutContents(dir+"/sub/b.txt", strings.Repeat("b", 100)))
+	fsutil.Must2(fsutil.PutContents(dir+"/sub/deep/c.log", strings.Repeat("c", 1000)))
+	fsutil.Must2(fsutil.PutContents(dir+"/z.txt", "z"))
+	return dir
+}
+
+func TestDirSize(t *testing.T) {
+	dir := makeUsageDir(t)
+	size, err := fsutil.DirSize(dir)
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(1111), size)
+
+	_, err = fsutil.DirSize(dir + "/not-exist")
+	assert.Err(t, err)
+}
+
+func TestDiskUsage(t *testing.T) {
+	dir := makeUsageDir(t)
+	root, err := fsutil.DiskUsage(dir, 1)
+	assert.NoErr(t, err)
+	assert.True(t, root.IsDir)
+	assert.Eq(t, int64(1111), root.Size)
+	assert.Eq(t, 2, root.Dirs)
+	assert.Eq(t, 4, root.Files)
+
+	// sorted by size desc, depth limited
+	assert.Len(t, root.Children, 3)
+	sub := root.Children[0]
+	assert.Eq(t, "sub", sub.Name)
+	assert.Eq(t, int64(1100), sub.Size)
+	assert.Empty(t, sub.Children)
+	assert.StrContains(t, sub.String(), "1.07K")
+
+	// no limit, with filters
+	root, err = fsutil.DiskUsage(dir, 0, fsutil.ExcludeSuffix(".log"))
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(111), root.Size)
+	assert.Len(t, root.Children[0].Children, 2)
+}
+
+func TestTopNFiles(t *testing.T) {
+	dir := makeUsageDir(t)
+	files, err := fsutil.TopNFiles(dir, 2)
+	assert.NoErr(t, err)
+	assert.Len(t, files, 2)
+	assert.Eq(t, "c.log", files[0].Name)
+	assert.Eq(t, "b.txt", files[1].Name)
+
+	files, err = fsutil.TopNFiles(dir, 0, fsutil.ExcludeSuffix(".log"))
+	assert.NoErr(t, err)
+	assert.Len(t, files, 3)
+	assert.Eq(t, "b.txt", files[0].Name)
+}
+
+func TestTree(t *testing.T) {
+	dir := makeUsageDir(t)
+	str, err := fsutil.Tree(dir)
+	assert.NoErr(t, err)
+	want := dir + `
+├── sub
+│   ├── deep
+│   │   └── c.log
+│   └── b.txt
+├── a.txt
+└── z.txt
+
+2 directories, 4 files
+`
+	assert.Eq(t, want, str)
+
+	str, err = fsutil.Tree(dir, fsutil.WithMaxDepth(1), fsutil.WithSizes)
+	assert.NoErr(t, err)
+	assert.StrContains(t, str, "├── [   1.07K]  sub\n├── [     10B]  a.txt\n")
+	assert.StrContains(t, str, "1 directories, 2 files")
+
+	str, err = fsutil.Tree(dir, fsutil.WithTreeFilters(fsutil.ExcludeNames("deep")), func(opt *fsutil.TreeOption) {
+		opt.DirsFirst = false
+	})
+	assert.NoErr(t, err)
+	assert.StrContains(t, str, "├── a.txt\n├── sub\n│   └── b.txt\n└── z.txt\n")
+
+	_, err = fsutil.Tree(dir + "/not-exist")
+	assert.Err(t, err)
+}
@@ -0,0 +1,286 @@
+package fsutil
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// ************************************************************
+//	filesystem abstraction
+// ************************************************************
+
+// ErrReadOnly the filesystem is read-only. eg: fs.FS, embed.FS
+var ErrReadOnly = errors.New("fsutil: read-only filesystem")
+
+// FS interface for the filesystem, extends the fs.FS with stat, read and write operations.
+//
+// Implements:
+//
+//   - OSFS: the local OS filesystem, see NewOSFS()
+//   - read-only fs.FS: eg: embed.FS, see NewReadOnlyFS()
+//   - MemFS: the in-memory filesystem, see NewMemFS()
+type FS interface {
+	fs.StatFS
+	fs.ReadFileFS
+	fs.ReadDirFS
+	// WriteFile writes data to the named file, creating it if necessary.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// MkdirAll creates a directory named path, along with any necessary parents.
+	MkdirAll(path string, perm fs.FileMode) error
+	// Remove removes the named file or (empty) directory.
+	Remove(name string) error
+	// RemoveAll removes path and any children it contains.
+	RemoveAll(path string) error
+}
+
+// LocalFS the local OS filesystem, allow the OS path. eg: "/path/to/file", "./file"
+var LocalFS FS = NewOSFS("")
+
+// OSFS the OS filesystem implements the FS interface.
+type OSFS struct {
+	root string
+}
+
+// NewOSFS create a new OS filesystem instance with root dir.
+//
+// If root is empty, the name will be used as OS path directly.
+// Otherwise, the name must be a valid fs.FS path, and relative to the root.
+func NewOSFS(root string) *OSFS {
+	return &OSFS{root: root}
+}
+
+// Root dir path of the filesystem
+func (o *OSFS) Root() string { return o.root }
+
+func (o *OSFS) toPath(op, name string) (string, error) {
+	if o.root == "" {
+		return name, nil
+	}
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(o.root, filepath.FromSlash(name)), nil
+}
+
+// Open the named file for reading
+func (o *OSFS) Open(name string) (fs.File, error) {
+	fPath, err := o.toPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(fPath)
+}
+
+// Stat returns a FileInfo describing the named file
+func (o *OSFS) Stat(name string) (fs.FileInfo, error) {
+	fPath, err := o.toPath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(fPath)
+}
+
+// ReadFile reads the named file and returns its contents
+func (o *OSFS) ReadFile(name string) ([]byte, error) {
+	fPath, err := o.toPath("read", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(fPath)
+}
+
+// ReadDir reads the named directory, returns all its directory entries sorted by filename.
+func (o *OSFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	fPath, err := o.toPath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadDir(fPath)
+}
+
+// WriteFile writes data to the named file, creating it if necessary.
+func (o *OSFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	fPath, err := o.toPath("write", name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fPath, data, perm)
+}
+
+// MkdirAll creates a directory named path, along with any necessary parents.
+func (o *OSFS) MkdirAll(name string, perm fs.FileMode) error {
+	fPath, err := o.toPath("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(fPath, perm)
+}
+
+// Remove removes the named file or (empty) directory.
+func (o *OSFS) Remove(name string) error {
+	fPath, err := o.toPath("remove", name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(fPath)
+}
+
+// RemoveAll removes path and any children it contains.
+func (o *OSFS) RemoveAll(name string) error {
+	fPath, err := o.toPath("remove", name)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(fPath)
+}
+
+// readOnlyFS wrap a fs.FS as read-only FS
+type readOnlyFS struct {
+	fs.FS
+}
+
+// NewReadOnlyFS wrap a fs.FS as read-only FS. eg: embed.FS, os.DirFS()
+//
+// All write operations will return ErrReadOnly.
+func NewReadOnlyFS(fsys fs.FS) FS {
+	return &readOnlyFS{FS: fsys}
+}
+
+func (r *readOnlyFS) Stat(name string) (fs.FileInfo, error) { return fs.Stat(r.FS, name) }
+
+func (r *readOnlyFS) ReadFile(name string) ([]byte, error) { return fs.ReadFile(r.FS, name) }
+
+func (r *readOnlyFS) ReadDir(name string) ([]fs.DirEntry, error) { return fs.ReadDir(r.FS, name) }
+
+func (r *readOnlyFS) WriteFile(name string, _ []byte, _ fs.FileMode) error {
+	return &fs.PathError{Op: "write", Path: name, Err: ErrReadOnly}
+}
+
+func (r *readOnlyFS) MkdirAll(name string, _ fs.FileMode) error {
+	return &fs.PathError{Op: "mkdir", Path: name, Err: ErrReadOnly}
+}
+
+func (r *readOnlyFS) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: ErrReadOnly}
+}
+
+func (r *readOnlyFS) RemoveAll(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: ErrReadOnly}
+}
+
+// ************************************************************
+//	helper functions for fs.FS and FS
+// ************************************************************
+
+// PathExistsFS reports whether the named file or directory exists in the fsys.
+func PathExistsFS(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
+
+// IsDirFS reports whether the named directory exists in the fsys.
+func IsDirFS(fsys fs.FS, name string) bool {
+	if fi, err := fs.Stat(fsys, name); err == nil {
+		return fi.IsDir()
+	}
+	return false
+}
+
+// IsFileFS reports whether the named file exists in the fsys.
+func IsFileFS(fsys fs.FS, name string) bool {
+	if fi, err := fs.Stat(fsys, name); err == nil {
+		return !fi.IsDir()
+	}
+	return false
+}
+
+// FileExistsFS reports whether the named file exists in the fsys. alias of IsFileFS()
+func FileExistsFS(fsys fs.FS, name string) bool { return IsFileFS(fsys, name) }
+
+// ReadFileFS reads the named file in the fsys. alias of fs.ReadFile()
+func ReadFileFS(fsys fs.FS, name string) ([]byte, error) { return fs.ReadFile(fsys, name) }
+
+// ReadStringFS reads the named file contents as string, returns empty on error.
+func ReadStringFS(fsys fs.FS, name string) string {
+	bs, _ := fs.ReadFile(fsys, name)
+	return string(bs)
+}
+
+// WriteFileFS write data to the named file in the fsys. will auto create parent dir.
+//
+// data type allows: string, []byte, io.Reader
+func WriteFileFS(fsys FS, name string, data any, perm fs.FileMode) error {
+	if dir := path.Dir(name); dir != "." && dir != "/" {
+		if err := fsys.MkdirAll(dir, DefaultDirPerm); err != nil {
+			return err
+		}
+	}
+
+	var bs []byte
+	switch typData := data.(type) {
+	case []byte:
+		bs = typData
+	case string:
+		bs = []byte(typData)
+	case io.Reader: // eg: buffer
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(typData); err != nil {
+			return err
+		}
+		bs = buf.Bytes()
+	default:
+		panic("WriteFileFS: data type only allow: []byte, string, io.Reader")
+	}
+	return fsys.WriteFile(name, bs, perm)
+}
+
+// GlobFS finds files by glob path pattern in the fsys. alias of fs.Glob()
+// and support filter matched files by name.
+func GlobFS(fsys fs.FS, pattern string, fls ...NameMatchFunc) []string {
+	files, _ := fs.Glob(fsys, pattern)
+	if len(fls) == 0 || len(files) == 0 {
+		return files
+	}
+
+	var matched []string
+	for _, file := range files {
+		for _, fn := range fls {
+			if fn(path.Base(file)) {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// FindInDirFS like FindInDir(), but find in the fsys.
+//
+// - TIP: default will be not found in sub-dir.
+//
+// filters: return false will skip the file.
+func FindInDirFS(fsys fs.FS, dir string, handleFn HandleFunc, filters ...FilterFunc) error {
+	des, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil // ignore I/O error
+	}
+
+	for _, ent := range des {
+		filePath := path.Join(dir, ent.Name())
+
+		// apply filters
+		if len(filters) > 0 && ApplyFilters(filePath, ent, filters) {
+			continue
+		}
+
+		if err1 := handleFn(filePath, ent); err1 != nil {
+			return err1
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package fsutil_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func writeTestFiles(t *testing.T, fsys fsutil.FS) {
+	assert.NoErr(t, fsutil.WriteFileFS(fsys, "a.txt", "hello", 0644))
+	assert.NoErr(t, fsutil.WriteFileFS(fsys, "sub/b.go", []byte("package sub"), 0644))
+	assert.NoErr(t, fsutil.WriteFileFS(fsys, "sub/deep/c.md", strings.NewReader("# title"), 0644))
+}
+
+func testFSHelpers(t *testing.T, fsys fsutil.FS) {
+	writeTestFiles(t, fsys)
+	assert.NoErr(t, fstest.TestFS(fsys, "a.txt", "sub/b.go", "sub/deep/c.md"))
+
+	assert.True(t, fsutil.PathExistsFS(fsys, "sub"))
+	assert.True(t, fsutil.IsDirFS(fsys, "sub/deep"))
+	assert.False(t, fsutil.IsDirFS(fsys, "a.txt"))
+	assert.True(t, fsutil.IsFileFS(fsys, "a.txt"))
+	assert.True(t, fsutil.FileExistsFS(fsys, "sub/b.go"))
+	assert.False(t, fsutil.FileExistsFS(fsys, "not-exist.txt"))
+	assert.Eq(t, "hello", fsutil.ReadStringFS(fsys, "a.txt"))
+
+	bs, err := fsutil.ReadFileFS(fsys, "sub/deep/c.md")
+	assert.NoErr(t, err)
+	assert.Eq(t, "# title", string(bs))
+
+	assert.Eq(t, []string{"sub/b.go"}, fsutil.GlobFS(fsys, "sub/*.go"))
+	assert.Eq(t, []string{"sub/b.go"}, fsutil.GlobFS(fsys, "sub/*", func(s string) bool {
+		return strings.HasSuffix(s, ".go")
+	}))
+
+	var files []string
+	err = fsutil.FindInDirFS(fsys, "sub", func(fPath string, ent fs.DirEntry) error {
+		files = append(files, fPath)
+		return nil
+	}, fsutil.OnlyFindFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"sub/b.go"}, files)
+
+	// remove
+	assert.Err(t, fsys.Remove("sub"))
+	assert.NoErr(t, fsys.Remove("a.txt"))
+	assert.False(t, fsutil.PathExistsFS(fsys, "a.txt"))
+	assert.NoErr(t, fsys.RemoveAll("sub"))
+	assert.False(t, fsutil.PathExistsFS(fsys, "sub/deep/c.md"))
+	assert.NoErr(t, fsys.RemoveAll("sub"))
+}
+
+func TestMemFS(t *testing.T) {
+	mfs := fsutil.NewMemFS()
+	testFSHelpers(t, mfs)
+
+	// errors
+	assert.Err(t, mfs.WriteFile("not-exist/a.txt", nil, 0644))
+	assert.Err(t, mfs.WriteFile("../a.txt", nil, 0644))
+	assert.NoErr(t, mfs.WriteFile("file", []byte("data"), 0600))
+	assert.Err(t, mfs.MkdirAll("file/sub", 0755))
+	assert.Err(t, mfs.WriteFile("file/a.txt", nil, 0644))
+	assert.NoErr(t, mfs.MkdirAll("dir", 0755))
+	assert.Err(t, mfs.WriteFile("dir", nil, 0644))
+
+	_, err := mfs.ReadFile("dir")
+	assert.Err(t, err)
+	_, err = mfs.ReadDir("file")
+	assert.Err(t, err)
+	_, err = mfs.Open("not-exist")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	fi, err := mfs.Stat("file")
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(4), fi.Size())
+	assert.Eq(t, fs.FileMode(0600), fi.Mode())
+}
+
+func TestOSFS(t *testing.T) {
+	ofs := fsutil.NewOSFS(t.TempDir())
+	assert.NotEmpty(t, ofs.Root())
+	testFSHelpers(t, ofs)
+
+	_, err := ofs.Open("../a.txt")
+	assert.Err(t, err)
+
+	// LocalFS allow OS path
+	assert.True(t, fsutil.IsDirFS(fsutil.LocalFS, ofs.Root()))
+}
+
+func TestNewReadOnlyFS(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, fsutil.NewOSFS(dir))
+
+	rfs := fsutil.NewReadOnlyFS(os.DirFS(dir))
+	assert.NoErr(t, fstest.TestFS(rfs, "a.txt", "sub/b.go", "sub/deep/c.md"))
+	assert.Eq(t, "hello", fsutil.ReadStringFS(rfs, "a.txt"))
+	assert.True(t, fsutil.IsDirFS(rfs, "sub"))
+
+	assert.ErrIs(t, rfs.WriteFile("a.txt", nil, 0644), fsutil.ErrReadOnly)
+	assert.ErrIs(t, fsutil.WriteFileFS(rfs, "sub/new.txt", "new", 0644), fsutil.ErrReadOnly)
+	assert.ErrIs(t, rfs.MkdirAll("new", 0755), fsutil.ErrReadOnly)
+	assert.ErrIs(t, rfs.Remove("a.txt"), fsutil.ErrReadOnly)
+	assert.ErrIs(t, rfs.RemoveAll("sub"), fsutil.ErrReadOnly)
+}
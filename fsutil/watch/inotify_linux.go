@@ -0,0 +1,275 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// watch events mask for inotify
+const inotifyMask = syscall.IN_CREATE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_DELETE | syscall.IN_DELETE_SELF | syscall.IN_MOVED_FROM |
+	syscall.IN_MOVED_TO | syscall.IN_MOVE_SELF
+
+// inotify backend on linux
+type inotify struct {
+	opt  *Options
+	fd   int
+	file *os.File
+
+	mu    sync.Mutex
+	wds   map[int]string // wd -> path
+	paths map[string]int // path -> wd
+	// dir path -> file names. on watch a file, will watch the parent dir
+	// and only report the events of the file names.
+	files map[string]map[string]bool
+
+	// last moved from dir, use for update the watch paths on moved to.
+	moveCookie uint32
+	movePath   string
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newNativeBackend(opt *Options) (backend, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inotify{
+		opt: opt,
+		fd:  fd,
+		// non-blocking fd will use the runtime poller, so the Close() can interrupt the Read().
+		file:  os.NewFile(uintptr(fd), "inotify"),
+		wds:   make(map[int]string),
+		paths: make(map[string]int),
+		files: make(map[string]map[string]bool),
+	}, nil
+}
+
+func (in *inotify) add(root string) error {
+	_, err := in.addTree(root, nil)
+	return err
+}
+
+// addTree add watch for the path, and all sub dirs on recursive.
+// the found sub paths will be appended to the found list.
+func (in *inotify) addTree(root string, found []string) ([]string, error) {
+	fi, err := os.Stat(root)
+	if err != nil {
+		return found, err
+	}
+	if !fi.IsDir() {
+		// watch the parent dir, so the watch is kept on the file replaced by rename. eg: atomic save
+		return found, in.addWatch(filepath.Dir(root), filepath.Base(root))
+	}
+	if !in.opt.Recursive {
+		return found, in.addWatch(root)
+	}
+
+	err = filepath.WalkDir(root, func(fPath string, ent fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if fPath == root {
+			return in.addWatch(fPath)
+		}
+
+		if in.opt.skip(fPath, ent) {
+			if ent.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		found = append(found, fPath)
+		if ent.IsDir() {
+			return in.addWatch(fPath)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// addWatch add watch for the dir. if names is not empty, only report the events of the names.
+func (in *inotify) addWatch(dir string, names ...string) error {
+	wd, err := syscall.InotifyAddWatch(in.fd, dir, inotifyMask)
+	if err != nil {
+		return &os.PathError{Op: "inotify_add_watch", Path: dir, Err: err}
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	// the dir has been moved, the same inode will return the same wd.
+	if old, ok := in.wds[wd]; ok && old != dir {
+		delete(in.paths, old)
+	}
+
+	_, watched := in.paths[dir]
+	only, limited := in.files[dir]
+	in.wds[wd] = dir
+	in.paths[dir] = wd
+
+	if len(names) == 0 {
+		delete(in.files, dir)
+	} else if !watched || limited {
+		if only == nil {
+			only = make(map[string]bool, len(names))
+			in.files[dir] = only
+		}
+		for _, name := range names {
+			only[name] = true
+		}
+	}
+	return nil
+}
+
+// moveWatches update the watch paths of the dir and sub dirs on the dir moved.
+func (in *inotify) moveWatches(oldDir, newDir string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	prefix := oldDir + string(filepath.Separator)
+	for wd, p := range in.wds {
+		if p != oldDir && !strings.HasPrefix(p, prefix) {
+			continue
+		}
+
+		np := newDir + p[len(oldDir):]
+		delete(in.paths, p)
+		in.wds[wd] = np
+		in.paths[np] = wd
+		if only, ok := in.files[p]; ok {
+			delete(in.files, p)
+			in.files[np] = only
+		}
+	}
+}
+
+func (in *inotify) run(ctx context.Context, ch chan<- Event, errCh chan<- error) {
+	go func() {
+		<-ctx.Done()
+		_ = in.close()
+	}()
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := in.file.Read(buf)
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, os.ErrClosed) {
+				sendErr(errCh, err)
+			}
+			return
+		}
+
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			raw := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			off += syscall.SizeofInotifyEvent
+
+			var name string
+			if raw.Len > 0 {
+				name = strings.TrimRight(string(buf[off:off+int(raw.Len)]), "\x00")
+				off += int(raw.Len)
+			}
+
+			for _, ev := range in.handle(int(raw.Wd), raw.Mask, raw.Cookie, name, errCh) {
+				select {
+				case ch <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle the inotify event, convert to the change events
+func (in *inotify) handle(wd int, mask, cookie uint32, name string, errCh chan<- error) []Event {
+	if mask&syscall.IN_Q_OVERFLOW != 0 {
+		sendErr(errCh, errors.New("watch: inotify event queue overflow"))
+		return nil
+	}
+
+	in.mu.Lock()
+	dir, ok := in.wds[wd]
+	only, limited := in.files[dir]
+	if ok && mask&syscall.IN_IGNORED != 0 {
+		delete(in.wds, wd)
+		if in.paths[dir] == wd {
+			delete(in.paths, dir)
+			delete(in.files, dir)
+		}
+	}
+	in.mu.Unlock()
+
+	if !ok || mask&syscall.IN_IGNORED != 0 {
+		return nil
+	}
+
+	fPath := dir
+	isDir := mask&syscall.IN_ISDIR != 0
+	if limited {
+		// the dir is watched for some files, skip the events of the dir self and other files.
+		if name == "" || !only[name] {
+			return nil
+		}
+		fPath = filepath.Join(dir, name)
+	} else if name != "" {
+		fPath = filepath.Join(dir, name)
+		if in.opt.skip(fPath, newPathEntry(fPath, isDir)) {
+			return nil
+		}
+	}
+
+	var evs []Event
+	switch {
+	case mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0:
+		evs = append(evs, Event{Path: fPath, Op: Create})
+
+		// the dir moved in the tree, update the watch paths of it.
+		if isDir && mask&syscall.IN_MOVED_TO != 0 && in.movePath != "" && cookie == in.moveCookie {
+			in.moveWatches(in.movePath, fPath)
+			in.movePath = ""
+		}
+
+		// watch the new dir, and the sub paths maybe created before watch.
+		if isDir && in.opt.Recursive {
+			found, err := in.addTree(fPath, nil)
+			if err != nil {
+				sendErr(errCh, err)
+			}
+			for _, p := range found {
+				evs = append(evs, Event{Path: p, Op: Create})
+			}
+		}
+	case mask&(syscall.IN_MODIFY|syscall.IN_CLOSE_WRITE) != 0:
+		evs = append(evs, Event{Path: fPath, Op: Write})
+	case mask&(syscall.IN_DELETE|syscall.IN_DELETE_SELF) != 0:
+		evs = append(evs, Event{Path: fPath, Op: Remove})
+	case mask&(syscall.IN_MOVED_FROM|syscall.IN_MOVE_SELF) != 0:
+		evs = append(evs, Event{Path: fPath, Op: Rename})
+		if isDir && mask&syscall.IN_MOVED_FROM != 0 {
+			in.moveCookie, in.movePath = cookie, fPath
+		}
+	}
+	return evs
+}
+
+func (in *inotify) close() error {
+	in.closeOnce.Do(func() {
+		in.closeErr = in.file.Close()
+	})
+	return in.closeErr
+}
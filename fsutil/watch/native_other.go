@@ -0,0 +1,8 @@
+//go:build !linux
+
+package watch
+
+// newNativeBackend on the OS not support inotify, will use the polling backend.
+func newNativeBackend(opt *Options) (backend, error) {
+	return newPoller(opt), nil
+}
@@ -0,0 +1,139 @@
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type fileState struct {
+	size  int64
+	mtime time.Time
+	mode  fs.FileMode
+}
+
+// poller backend, scan the watched paths by interval and compare the changes.
+type poller struct {
+	opt   *Options
+	mu    sync.Mutex
+	roots []string
+	state map[string]fileState
+}
+
+func newPoller(opt *Options) *poller {
+	return &poller{opt: opt, state: make(map[string]fileState)}
+}
+
+func (p *poller) add(root string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.roots = append(p.roots, root)
+	return p.scan(root, p.state)
+}
+
+func (p *poller) run(ctx context.Context, ch chan<- Event, errCh chan<- error) {
+	ticker := time.NewTicker(p.opt.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, ev := range p.poll(errCh) {
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// poll scan all roots and returns the change events
+func (p *poller) poll(errCh chan<- error) []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := make(map[string]fileState, len(p.state))
+	for _, root := range p.roots {
+		if err := p.scan(root, state); err != nil {
+			sendErr(errCh, err)
+		}
+	}
+
+	var evs []Event
+	for fPath, st := range state {
+		old, ok := p.state[fPath]
+		if !ok {
+			evs = append(evs, Event{Path: fPath, Op: Create})
+		} else if !st.mode.IsDir() && (st.size != old.size || !st.mtime.Equal(old.mtime)) {
+			evs = append(evs, Event{Path: fPath, Op: Write})
+		}
+	}
+	for fPath := range p.state {
+		if _, ok := state[fPath]; !ok {
+			evs = append(evs, Event{Path: fPath, Op: Remove})
+		}
+	}
+
+	p.state = state
+	sort.Slice(evs, func(i, j int) bool { return evs[i].Path < evs[j].Path })
+	return evs
+}
+
+// scan the root path to state map
+func (p *poller) scan(root string, state map[string]fileState) error {
+	fi, err := os.Lstat(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	state[root] = fileState{size: fi.Size(), mtime: fi.ModTime(), mode: fi.Mode()}
+	if !fi.IsDir() {
+		return nil
+	}
+
+	return filepath.WalkDir(root, func(fPath string, ent fs.DirEntry, err error) error {
+		if err != nil {
+			// the path removed on walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if fPath == root {
+			return nil
+		}
+
+		if p.opt.skip(fPath, ent) {
+			if ent.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		info, err := ent.Info()
+		if err != nil {
+			return nil
+		}
+
+		state[fPath] = fileState{size: info.Size(), mtime: info.ModTime(), mode: info.Mode()}
+		if ent.IsDir() && !p.opt.Recursive {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
+
+func (p *poller) close() error { return nil }
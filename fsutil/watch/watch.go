@@ -0,0 +1,390 @@
+// Package watch provide a simple filesystem watcher, watch files or dir trees
+// and deliver the debounced change events on a channel.
+//
+// On linux use the inotify, other OS will use the polling backend.
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/finder"
+)
+
+// Op describes the change operations on a file
+type Op uint8
+
+// change operations.
+//
+// NOTE: the polling backend can not detect the rename, will report it as Remove of the
+// old path and Create of the new path.
+const (
+	Create Op = 1 << iota
+	Write
+	Remove
+	Rename
+)
+
+// Has check the op has the given op
+func (op Op) Has(o Op) bool { return op&o != 0 }
+
+// String get op string. eg: "CREATE|WRITE"
+func (op Op) String() string {
+	var ss []string
+	if op.Has(Create) {
+		ss = append(ss, "CREATE")
+	}
+	if op.Has(Write) {
+		ss = append(ss, "WRITE")
+	}
+	if op.Has(Remove) {
+		ss = append(ss, "REMOVE")
+	}
+	if op.Has(Rename) {
+		ss = append(ss, "RENAME")
+	}
+	return strings.Join(ss, "|")
+}
+
+// Event a change event of the file or dir.
+type Event struct {
+	// Path of the changed file or dir
+	Path string
+	// Op merged change operations in the debounce window
+	Op Op
+}
+
+// String get event string. eg: "CREATE|WRITE path/to/file.txt"
+func (e Event) String() string { return e.Op.String() + " " + e.Path }
+
+// Options for the watcher
+type Options struct {
+	// Recursive watch the sub dirs. default is true
+	Recursive bool
+	// Debounce duration for merge the burst events to a batch. default is 100ms
+	Debounce time.Duration
+	// MaxWait the max duration for wait a batch on continuous events. default is 1s
+	MaxWait time.Duration
+	// Polling use the polling backend. will auto use it on the OS not support inotify.
+	//
+	// NOTE: polling backend never emit the Rename event.
+	Polling bool
+	// PollInterval for polling backend. default is 500ms
+	PollInterval time.Duration
+	// Filters for filter the files and dirs. return false will skip it.
+	//
+	// NOTE: on file removed, the ent.Info() will return error.
+	Filters []fsutil.FilterFunc
+}
+
+// OptionFunc for the watcher
+type OptionFunc func(opt *Options)
+
+// NoRecursive only watch the direct children of the dir
+func NoRecursive(opt *Options) {
+	opt.Recursive = false
+}
+
+// WithDebounce set the debounce duration
+func WithDebounce(d time.Duration) OptionFunc {
+	return func(opt *Options) {
+		opt.Debounce = d
+	}
+}
+
+// WithMaxWait set the max wait duration of a batch, the pending events will be
+// sent even if new events keep coming.
+func WithMaxWait(d time.Duration) OptionFunc {
+	return func(opt *Options) {
+		opt.MaxWait = d
+	}
+}
+
+// WithPolling use the polling backend and set poll interval
+func WithPolling(interval time.Duration) OptionFunc {
+	return func(opt *Options) {
+		opt.Polling = true
+		opt.PollInterval = interval
+	}
+}
+
+// WithFilters add filters for the watcher. see fsutil.FilterFunc
+func WithFilters(fns ...fsutil.FilterFunc) OptionFunc {
+	return func(opt *Options) {
+		opt.Filters = append(opt.Filters, fns...)
+	}
+}
+
+// WithFinder use the include and exclude rules of the finder config.
+//
+// Usage:
+//
+//	c := finder.NewConfig()
+//	c.IncludeExts = []string{".go"}
+//	w := watch.New(watch.WithFinder(c))
+func WithFinder(c *finder.Config) OptionFunc {
+	return func(opt *Options) {
+		opt.Filters = append(opt.Filters, c.FilterFunc())
+	}
+}
+
+// skip check the path should be skipped by filters
+func (o *Options) skip(fPath string, ent fs.DirEntry) bool {
+	return len(o.Filters) > 0 && fsutil.ApplyFilters(fPath, ent, o.Filters)
+}
+
+// backend for watch the changes
+type backend interface {
+	// add watch the path. path is cleaned abs path
+	add(path string) error
+	// run until ctx is done, send change events to ch.
+	run(ctx context.Context, ch chan<- Event, errCh chan<- error)
+	close() error
+}
+
+// Watcher struct
+type Watcher struct {
+	opt   *Options
+	paths []string
+
+	be     backend
+	events chan []Event
+	errors chan error
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// New create a new Watcher instance
+func New(optFns ...OptionFunc) *Watcher {
+	opt := &Options{
+		Recursive:    true,
+		Debounce:     100 * time.Millisecond,
+		MaxWait:      time.Second,
+		PollInterval: 500 * time.Millisecond,
+	}
+	for _, fn := range optFns {
+		fn(opt)
+	}
+
+	return &Watcher{
+		opt:    opt,
+		events: make(chan []Event, 8),
+		errors: make(chan error, 8),
+	}
+}
+
+// Watch the paths and returns the batched events channel.
+// the channel will be closed on ctx done.
+//
+// Usage:
+//
+//	ch, err := watch.Watch(ctx, []string{"./"}, watch.WithFilters(fsutil.ExcludeDotFile))
+//	for evs := range ch {
+//		fmt.Println(evs)
+//	}
+func Watch(ctx context.Context, paths []string, optFns ...OptionFunc) (<-chan []Event, error) {
+	w := New(optFns...)
+	if err := w.Add(paths...); err != nil {
+		return nil, err
+	}
+	if err := w.Start(ctx); err != nil {
+		return nil, err
+	}
+	return w.Events(), nil
+}
+
+// Add paths to watch. if the watcher is started, will add to the backend.
+func (w *Watcher) Add(paths ...string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, p := range paths {
+		absPath, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+		if _, err = os.Stat(absPath); err != nil {
+			return err
+		}
+
+		w.paths = append(w.paths, absPath)
+		if w.be != nil {
+			if err = w.be.add(absPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Events channel for receive the batched events
+func (w *Watcher) Events() <-chan []Event { return w.events }
+
+// Errors channel for receive the backend errors.
+// the errors will be dropped if the channel is full.
+func (w *Watcher) Errors() <-chan error { return w.errors }
+
+// Start the watcher, will stop on ctx done or call Close().
+func (w *Watcher) Start(ctx context.Context) (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.be != nil {
+		return errors.New("watch: the watcher has been started")
+	}
+
+	if w.opt.Polling {
+		w.be = newPoller(w.opt)
+	} else if w.be, err = newNativeBackend(w.opt); err != nil {
+		return err
+	}
+
+	for _, p := range w.paths {
+		if err = w.be.add(p); err != nil {
+			_ = w.be.close()
+			return err
+		}
+	}
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	raw := make(chan Event, 64)
+	go func() {
+		w.be.run(ctx, raw, w.errors)
+		close(raw)
+	}()
+	go w.debounce(ctx, raw)
+	return nil
+}
+
+// Close stop the watcher. the pending events will be sent before the events channel closed.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel == nil {
+		return nil
+	}
+	w.cancel()
+	w.cancel = nil
+	return w.be.close()
+}
+
+// debounce merge the raw events and send a batch after no new events in the debounce duration,
+// or the batch has been waited for the max wait duration.
+func (w *Watcher) debounce(ctx context.Context, raw <-chan Event) {
+	defer close(w.events)
+
+	var pending []Event
+	var firstAt time.Time
+	index := make(map[string]int)
+	merge := func(ev Event) {
+		if i, has := index[ev.Path]; has {
+			pending[i].Op |= ev.Op
+			return
+		}
+		if len(pending) == 0 {
+			firstAt = time.Now()
+		}
+		index[ev.Path] = len(pending)
+		pending = append(pending, ev)
+	}
+
+	timer := time.NewTimer(w.opt.Debounce)
+	stopTimer(timer)
+
+	for {
+		select {
+		case ev, ok := <-raw:
+			if !ok {
+				if len(pending) > 0 {
+					select {
+					case w.events <- pending:
+					case <-ctx.Done():
+					}
+				}
+				return
+			}
+			merge(ev)
+
+			wait := w.opt.Debounce
+			if w.opt.MaxWait > 0 {
+				if remain := w.opt.MaxWait - time.Since(firstAt); remain < wait {
+					wait = remain
+				}
+			}
+			stopTimer(timer)
+			timer.Reset(wait)
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+
+			select {
+			case w.events <- pending:
+			case <-ctx.Done():
+				continue // will flush on next loop
+			}
+			pending = nil
+			index = make(map[string]int)
+		case <-ctx.Done():
+			// merge the remaining raw events, the raw will be closed after backend stopped.
+			for ev := range raw {
+				merge(ev)
+			}
+			// flush pending events, will not block if the channel is full.
+			if len(pending) > 0 {
+				select {
+				case w.events <- pending:
+				default:
+				}
+			}
+			return
+		}
+	}
+}
+
+func stopTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+}
+
+func sendErr(errCh chan<- error, err error) {
+	select {
+	case errCh <- err:
+	default:
+	}
+}
+
+// pathEntry implements fs.DirEntry for the changed path, use for apply filters.
+type pathEntry struct {
+	path  string
+	isDir bool
+}
+
+func newPathEntry(fPath string, isDir bool) fs.DirEntry {
+	return &pathEntry{path: fPath, isDir: isDir}
+}
+
+func (e *pathEntry) Name() string { return filepath.Base(e.path) }
+
+func (e *pathEntry) IsDir() bool { return e.isDir }
+
+func (e *pathEntry) Type() fs.FileMode {
+	if e.isDir {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+func (e *pathEntry) Info() (fs.FileInfo, error) { return os.Lstat(e.path) }
@@ -0,0 +1,190 @@
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/fsutil/watch"
+	"github.com/gookit/goutil/x/assert"
+	"github.com/gookit/goutil/x/finder"
+)
+
+// collect events until the path got the op, or timeout
+func waitEvent(t *testing.T, ch <-chan []watch.Event, fPath string, op watch.Op) watch.Event {
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case evs, ok := <-ch:
+			if !ok {
+				t.Fatalf("events channel closed, want %s %s", op, fPath)
+			}
+			for _, ev := range evs {
+				if ev.Path == fPath && ev.Op.Has(op) {
+					return ev
+				}
+			}
+		case <-timeout:
+			t.Fatalf("wait event timeout, want %s %s", op, fPath)
+		}
+	}
+}
+
+func testWatcher(t *testing.T, optFns ...watch.OptionFunc) {
+	dir := t.TempDir()
+	fsutil.Must2(fsutil.PutContents(dir+"/exist.txt", "hello"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	optFns = append(optFns, watch.WithDebounce(30*time.Millisecond), watch.WithFilters(fsutil.ExcludeSuffix(".log")))
+	ch, err := watch.Watch(ctx, []string{dir}, optFns...)
+	assert.NoErr(t, err)
+
+	// create
+	newFile := filepath.Join(dir, "sub", "new.txt")
+	fsutil.Must2(fsutil.PutContents(newFile, "new"))
+	waitEvent(t, ch, newFile, watch.Create)
+
+	// write
+	time.Sleep(50 * time.Millisecond)
+	fsutil.Must2(fsutil.PutContents(dir+"/exist.txt", "hello world"))
+	waitEvent(t, ch, filepath.Join(dir, "exist.txt"), watch.Write)
+
+	// remove
+	assert.NoErr(t, os.Remove(newFile))
+	waitEvent(t, ch, newFile, watch.Remove)
+
+	cancel()
+	for range ch {
+		// wait closed
+	}
+}
+
+func TestWatch_polling(t *testing.T) {
+	testWatcher(t, watch.WithPolling(20*time.Millisecond))
+}
+
+func TestWatch_native(t *testing.T) {
+	testWatcher(t)
+}
+
+func TestWatch_file(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "app.conf")
+	fsutil.Must2(fsutil.PutContents(cfgFile, "v1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := watch.Watch(ctx, []string{cfgFile}, watch.WithDebounce(30*time.Millisecond))
+	assert.NoErr(t, err)
+
+	// the watch should be kept after the file replaced by rename
+	for _, val := range []string{"v2", "v3"} {
+		assert.NoErr(t, fsutil.AtomicWrite(cfgFile, val))
+		ev := waitEvent(t, ch, cfgFile, watch.Create|watch.Write)
+		assert.Eq(t, cfgFile, ev.Path)
+	}
+
+	cancel()
+	for evs := range ch {
+		for _, ev := range evs {
+			assert.Eq(t, cfgFile, ev.Path)
+		}
+	}
+}
+
+func TestWatch_moveDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoErr(t, os.MkdirAll(dir+"/old/sub", 0755))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := watch.Watch(ctx, []string{dir}, watch.WithDebounce(30*time.Millisecond))
+	assert.NoErr(t, err)
+
+	assert.NoErr(t, os.Rename(dir+"/old", dir+"/new"))
+	waitEvent(t, ch, filepath.Join(dir, "new"), watch.Create)
+
+	newFile := filepath.Join(dir, "new", "sub", "a.txt")
+	fsutil.Must2(fsutil.PutContents(newFile, "a"))
+	waitEvent(t, ch, newFile, watch.Create)
+}
+
+func TestWatcher_maxWait(t *testing.T) {
+	dir := t.TempDir()
+	fPath := filepath.Join(dir, "a.txt")
+
+	w := watch.New(watch.WithDebounce(200*time.Millisecond), watch.WithMaxWait(100*time.Millisecond))
+	assert.NoErr(t, w.Add(dir))
+	assert.NoErr(t, w.Start(context.Background()))
+	defer w.Close()
+
+	// keep writing, the batch should be sent before the writes stopped.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 30; i++ {
+			fsutil.Must2(fsutil.PutContents(fPath, strconv.Itoa(i)))
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	waitEvent(t, w.Events(), fPath, watch.Create|watch.Write)
+	select {
+	case <-done:
+		t.Fatal("the batch should be sent before the writes stopped")
+	default:
+	}
+	<-done
+}
+
+func TestWatcher_Close_flush(t *testing.T) {
+	dir := t.TempDir()
+	fPath := filepath.Join(dir, "a.txt")
+
+	w := watch.New(watch.WithDebounce(time.Minute), watch.WithPolling(20*time.Millisecond))
+	assert.NoErr(t, w.Add(dir))
+	assert.NoErr(t, w.Start(context.Background()))
+
+	fsutil.Must2(fsutil.PutContents(fPath, "a"))
+	time.Sleep(150 * time.Millisecond)
+	assert.NoErr(t, w.Close())
+	waitEvent(t, w.Events(), fPath, watch.Create)
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	c := finder.NewConfig()
+	c.IncludeExts = []string{".go"}
+
+	w := watch.New(watch.WithFinder(c), watch.WithDebounce(30*time.Millisecond), watch.NoRecursive)
+	assert.Err(t, w.Add(dir+"/not-exist"))
+	assert.NoErr(t, w.Add(dir))
+	assert.NoErr(t, w.Start(context.Background()))
+	assert.Err(t, w.Start(context.Background()))
+
+	fsutil.Must2(fsutil.PutContents(dir+"/a.txt", "a"))
+	fsutil.Must2(fsutil.PutContents(dir+"/b.go", "b"))
+	ev := waitEvent(t, w.Events(), filepath.Join(dir, "b.go"), watch.Create)
+	assert.Contains(t, ev.String(), "CREATE")
+
+	assert.NoErr(t, w.Close())
+	assert.NoErr(t, w.Close())
+	for evs := range w.Events() {
+		for _, ev := range evs {
+			assert.NotEq(t, filepath.Join(dir, "a.txt"), ev.Path)
+		}
+	}
+}
+
+func TestOp_String(t *testing.T) {
+	assert.Eq(t, "CREATE", watch.Create.String())
+	assert.Eq(t, "REMOVE|RENAME", (watch.Remove | watch.Rename).String())
+	assert.True(t, (watch.Create | watch.Write).Has(watch.Write))
+	assert.False(t, watch.Create.Has(watch.Write))
+}
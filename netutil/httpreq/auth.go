@@ -0,0 +1,107 @@
+package httpreq
+
+import (
+	"io"
+	"net/http"
+)
+
+// Authenticator apply the auth info to the request. see Client.UseAuth()
+type Authenticator interface {
+	Apply(req *http.Request) error
+}
+
+// AuthChallenger an Authenticator can handle the 401 Unauthorized response.
+//
+// If Challenge returns true, the request will be re-applied and resent once.
+type AuthChallenger interface {
+	Authenticator
+	Challenge(resp *http.Response) bool
+}
+
+// AuthFunc func implements the Authenticator
+type AuthFunc func(req *http.Request) error
+
+// Apply the auth info to the request
+func (fn AuthFunc) Apply(req *http.Request) error { return fn(req) }
+
+// Apply set the basic auth header to the request
+func (ba *BasicAuthConf) Apply(req *http.Request) error {
+	req.SetBasicAuth(ba.Username, ba.Password)
+	return nil
+}
+
+// BasicAuth create a basic auth Authenticator
+func BasicAuth(username, password string) Authenticator {
+	return &BasicAuthConf{Username: username, Password: password}
+}
+
+// BearerAuth create an Authenticator, will set header "Authorization: Bearer TOKEN"
+func BearerAuth(token string) Authenticator {
+	return AuthFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// APIKeyHeader create an Authenticator, will set the API key to the header.
+//
+// Usage:
+//
+//	cli.UseAuth(httpreq.APIKeyHeader("X-API-Key", "my-key"))
+func APIKeyHeader(name, key string) Authenticator {
+	return AuthFunc(func(req *http.Request) error {
+		req.Header.Set(name, key)
+		return nil
+	})
+}
+
+// APIKeyQuery create an Authenticator, will add the API key to the URL query.
+func APIKeyQuery(name, key string) Authenticator {
+	return AuthFunc(func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set(name, key)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	})
+}
+
+// AuthMiddleware apply the auth info to each request.
+//
+// If the Authenticator is an AuthChallenger, will resend the request once on the response is 401,
+// and the request body must be re-readable(req.GetBody is not nil).
+func AuthMiddleware(a Authenticator) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := applyAndDo(next, a, req)
+			if err != nil || resp.StatusCode != http.StatusUnauthorized {
+				return resp, err
+			}
+
+			ch, ok := a.(AuthChallenger)
+			if !ok || !ch.Challenge(resp) {
+				return resp, nil
+			}
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				return resp, nil
+			}
+
+			// drain and close the body for reuse connection
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+
+			if req, err = rewindRequest(req); err != nil {
+				return nil, err
+			}
+			return applyAndDo(next, a, req)
+		})
+	}
+}
+
+// apply auth to a cloned request, avoid modify the original request.
+func applyAndDo(next Doer, a Authenticator, req *http.Request) (*http.Response, error) {
+	ar := req.Clone(req.Context())
+	if err := a.Apply(ar); err != nil {
+		return nil, err
+	}
+	return next.Do(ar)
+}
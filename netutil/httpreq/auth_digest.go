@@ -0,0 +1,185 @@
+package httpreq
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gookit/goutil/strutil"
+)
+
+// DigestAuth the HTTP digest access authentication. RFC 7616
+//
+// The first request will get a 401 response with the challenge, then resend the request with
+// the digest auth header. the challenge is cached for the next requests.
+//
+// Supported algorithms: MD5, MD5-sess, SHA-256, SHA-256-sess. only supported the qop "auth".
+//
+// Usage:
+//
+//	cli.UseAuth(httpreq.NewDigestAuth("admin", "pass"))
+type DigestAuth struct {
+	Username string
+	Password string
+
+	mu sync.Mutex
+	nc int
+	// the challenge params from the server
+	params map[string]string
+}
+
+// NewDigestAuth create a DigestAuth
+func NewDigestAuth(username, password string) *DigestAuth {
+	return &DigestAuth{Username: username, Password: password}
+}
+
+// Apply set the digest auth header, if the challenge is received.
+func (da *DigestAuth) Apply(req *http.Request) error {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	if da.params == nil {
+		return nil
+	}
+
+	da.nc++
+	val, err := da.authorization(req.Method, req.URL.RequestURI())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", val)
+	return nil
+}
+
+// Challenge parse the digest challenge from the 401 response
+func (da *DigestAuth) Challenge(resp *http.Response) bool {
+	for _, val := range resp.Header.Values("WWW-Authenticate") {
+		if len(val) < 7 || !strings.EqualFold(val[:7], "Digest ") {
+			continue
+		}
+
+		params := parseAuthParams(val[7:])
+		if params["nonce"] == "" {
+			return false
+		}
+
+		da.mu.Lock()
+		defer da.mu.Unlock()
+
+		// same nonce and not stale, the username or password is wrong.
+		if da.params != nil && da.params["nonce"] == params["nonce"] && !strings.EqualFold(params["stale"], "true") {
+			return false
+		}
+
+		da.nc = 0
+		da.params = params
+		return true
+	}
+	return false
+}
+
+// build the Authorization header value. NOTE: must be called with lock
+func (da *DigestAuth) authorization(method, uri string) (string, error) {
+	p := da.params
+	algo := strutil.OrElse(p["algorithm"], "MD5")
+	upAlgo := strings.ToUpper(algo)
+
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(upAlgo, "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("httpreq: unsupported digest algorithm %q", algo)
+	}
+
+	h := func(s string) string {
+		hh := newHash()
+		hh.Write([]byte(s))
+		return hex.EncodeToString(hh.Sum(nil))
+	}
+
+	nonce, realm := p["nonce"], p["realm"]
+	nc := fmt.Sprintf("%08x", da.nc)
+	bs, err := strutil.RandomBytes(8)
+	if err != nil {
+		return "", err
+	}
+	cnonce := hex.EncodeToString(bs)
+
+	ha1 := h(da.Username + ":" + realm + ":" + da.Password)
+	if strings.HasSuffix(upAlgo, "-SESS") {
+		ha1 = h(ha1 + ":" + nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+
+	var qop string
+	for _, q := range strings.Split(p["qop"], ",") {
+		if strings.TrimSpace(q) == "auth" {
+			qop = "auth"
+			break
+		}
+	}
+
+	var response string
+	if qop != "" {
+		response = h(strings.Join([]string{ha1, nonce, nc, cnonce, qop, ha2}, ":"))
+	} else {
+		response = h(ha1 + ":" + nonce + ":" + ha2)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", algorithm=%s, response="%s"`,
+		da.Username, realm, nonce, uri, algo, response))
+	if qop != "" {
+		sb.WriteString(fmt.Sprintf(`, qop=%s, nc=%s, cnonce="%s"`, qop, nc, cnonce))
+	}
+	if opaque, ok := p["opaque"]; ok {
+		sb.WriteString(fmt.Sprintf(`, opaque="%s"`, opaque))
+	}
+	return sb.String(), nil
+}
+
+// parse the auth params. eg: `realm="test", qop="auth,auth-int", nonce="abc"`
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " ")
+
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			// quoted value, allow escaped quote
+			sb := strings.Builder{}
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				sb.WriteByte(s[i])
+			}
+			val = sb.String()
+			if i < len(s) {
+				i++
+			}
+			s = s[i:]
+		} else if end := strings.IndexByte(s, ','); end >= 0 {
+			val, s = strings.TrimSpace(s[:end]), s[end:]
+		} else {
+			val, s = strings.TrimSpace(s), ""
+		}
+		params[key] = val
+	}
+	return params
+}
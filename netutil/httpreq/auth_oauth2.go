@@ -0,0 +1,197 @@
+package httpreq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gookit/goutil/netutil/httpctype"
+)
+
+// OAuth2Token the OAuth2 token response
+type OAuth2Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// ExpiresIn seconds from the token response
+	ExpiresIn int64 `json:"expires_in,omitempty"`
+	// Expiry time of the access token. zero means never expires.
+	Expiry time.Time `json:"expiry"`
+}
+
+// Valid check the token is not empty and not expired
+func (t *OAuth2Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Before(t.Expiry))
+}
+
+// OAuth2Config for OAuth2Auth
+type OAuth2Config struct {
+	// TokenURL the token endpoint
+	TokenURL string
+	// ClientID and ClientSecret of the client
+	ClientID, ClientSecret string
+	// Scopes optional request scopes
+	Scopes []string
+	// RefreshToken for the "refresh_token" grant. if empty, will use the "client_credentials" grant.
+	RefreshToken string
+	// AuthInParams send the client ID and secret in the form params. default use the basic auth header.
+	AuthInParams bool
+	// ExpiryDelta refresh the token before it expires. default is 10s
+	ExpiryDelta time.Duration
+	// Doer for request the token. default is http.DefaultClient
+	Doer Doer
+	// OnToken hook on got a new token. eg: save the rotated refresh token
+	OnToken func(tk *OAuth2Token)
+}
+
+// OAuth2Auth an OAuth2 Authenticator with the client-credentials or refresh-token grant.
+//
+// The token is cached until expiry, and will be refreshed on the response is 401.
+//
+// Usage:
+//
+//	cli.UseAuth(httpreq.NewOAuth2Auth("https://auth.example.com/oauth/token", "client-id", "secret",
+//		func(c *httpreq.OAuth2Config) {
+//			c.Scopes = []string{"read", "write"}
+//		},
+//	))
+type OAuth2Auth struct {
+	OAuth2Config
+	mu    sync.Mutex
+	token *OAuth2Token
+}
+
+// NewOAuth2Auth create an OAuth2Auth
+func NewOAuth2Auth(tokenURL, clientID, clientSecret string, fns ...func(c *OAuth2Config)) *OAuth2Auth {
+	oa := &OAuth2Auth{
+		OAuth2Config: OAuth2Config{
+			TokenURL:     tokenURL,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			ExpiryDelta:  10 * time.Second,
+			Doer:         http.DefaultClient,
+		},
+	}
+
+	for _, fn := range fns {
+		fn(&oa.OAuth2Config)
+	}
+	return oa
+}
+
+// Apply set the access token to the request header. will fetch a new token if not valid.
+func (oa *OAuth2Auth) Apply(req *http.Request) error {
+	tk, err := oa.Token(req.Context())
+	if err != nil {
+		return err
+	}
+
+	typ := tk.TokenType
+	if typ == "" || strings.EqualFold(typ, "bearer") {
+		typ = "Bearer"
+	}
+	req.Header.Set("Authorization", typ+" "+tk.AccessToken)
+	return nil
+}
+
+// Challenge invalidate the cached token on the response is 401, the request will be resent with a new token.
+func (oa *OAuth2Auth) Challenge(_ *http.Response) bool {
+	oa.mu.Lock()
+	defer oa.mu.Unlock()
+	if oa.token == nil {
+		return false
+	}
+
+	oa.token = nil
+	return true
+}
+
+// Token get the cached token, or fetch a new token if not valid.
+func (oa *OAuth2Auth) Token(ctx context.Context) (*OAuth2Token, error) {
+	oa.mu.Lock()
+	defer oa.mu.Unlock()
+
+	if oa.token.Valid() {
+		return oa.token, nil
+	}
+
+	tk, err := oa.fetchToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oa.token = tk
+	if tk.RefreshToken != "" {
+		oa.RefreshToken = tk.RefreshToken
+	}
+	if oa.OnToken != nil {
+		oa.OnToken(tk)
+	}
+	return tk, nil
+}
+
+func (oa *OAuth2Auth) fetchToken(ctx context.Context) (*OAuth2Token, error) {
+	form := url.Values{}
+	if oa.RefreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", oa.RefreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+	if len(oa.Scopes) > 0 {
+		form.Set("scope", strings.Join(oa.Scopes, " "))
+	}
+	if oa.AuthInParams {
+		form.Set("client_id", oa.ClientID)
+		form.Set("client_secret", oa.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, oa.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(httpctype.Key, httpctype.MIMEForm)
+	req.Header.Set("Accept", httpctype.MIMEJSON)
+	if !oa.AuthInParams {
+		req.SetBasicAuth(url.QueryEscape(oa.ClientID), url.QueryEscape(oa.ClientSecret))
+	}
+
+	resp, err := oa.Doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := readLimited(resp.Body, 1<<20)
+	if !IsSuccessful(resp.StatusCode) {
+		return nil, &HTTPError{
+			Method:     req.Method,
+			URL:        oa.TokenURL,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header,
+			Body:       body,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tk := &OAuth2Token{}
+	if err := json.Unmarshal(body, tk); err != nil {
+		return nil, err
+	}
+	if tk.AccessToken == "" {
+		return nil, errors.New("httpreq: no access_token in the OAuth2 token response")
+	}
+
+	if tk.ExpiresIn > 0 {
+		tk.Expiry = time.Now().Add(time.Duration(tk.ExpiresIn)*time.Second - oa.ExpiryDelta)
+	}
+	return tk, nil
+}
@@ -0,0 +1,168 @@
+package httpreq_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestClient_UseAuth(t *testing.T) {
+	tests := []struct {
+		auth   httpreq.Authenticator
+		header string
+		want   string
+	}{
+		{httpreq.BearerAuth("my-token"), "Authorization", "Bearer my-token"},
+		{httpreq.BasicAuth("admin", "pass"), "Authorization", httpreq.BuildBasicAuth("admin", "pass")},
+		{httpreq.APIKeyHeader("X-API-Key", "my-key"), "X-Api-Key", "my-key"},
+	}
+
+	for _, tt := range tests {
+		resp, err := httpreq.New(testSrvAddr).UseAuth(tt.auth).Get("/get")
+		assert.NoErr(t, err)
+		rr := testutil.ParseRespToReply(resp)
+		assert.Eq(t, tt.want, rr.Headers[tt.header])
+	}
+
+	resp, err := httpreq.New(testSrvAddr).UseAuth(httpreq.APIKeyQuery("api_key", "my-key")).Get("/get?id=1")
+	assert.NoErr(t, err)
+	rr := testutil.ParseRespToReply(resp)
+	assert.Eq(t, "my-key", rr.Query["api_key"])
+	assert.Eq(t, "1", rr.Query["id"])
+
+	// apply error
+	_, err = httpreq.New(testSrvAddr).UseAuth(httpreq.AuthFunc(func(req *http.Request) error {
+		return errors.New("no token")
+	})).Get("/get")
+	assert.ErrMsg(t, err, "no token")
+}
+
+func TestDigestAuth(t *testing.T) {
+	var calls int32
+	h := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Digest ") {
+			w.Header().Set("WWW-Authenticate", `Digest realm="test", qop="auth,auth-int", nonce="abc123", opaque="xyz"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// verify the response
+		p := map[string]string{}
+		for _, part := range strings.Split(auth[7:], ", ") {
+			k, v, _ := strings.Cut(part, "=")
+			p[k] = strings.Trim(v, `"`)
+		}
+
+		ha1 := h("admin:test:" + r.Header.Get("X-Password"))
+		ha2 := h(r.Method + ":" + p["uri"])
+		want := h(strings.Join([]string{ha1, p["nonce"], p["nc"], p["cnonce"], p["qop"], ha2}, ":"))
+		if p["response"] != want || p["opaque"] != "xyz" || p["uri"] != r.URL.RequestURI() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "ok, nc=%s", p["nc"])
+	}))
+	defer srv.Close()
+
+	da := httpreq.NewDigestAuth("admin", "pass")
+	cli := httpreq.New(srv.URL).UseAuth(da).DefaultHeader("X-Password", "pass")
+
+	rx, err := httpreq.WrapResp(cli.Get("/data?id=1"))
+	assert.NoErr(t, err)
+	assert.Eq(t, 200, rx.StatusCode)
+	assert.Eq(t, "ok, nc=00000001", rx.BodyString())
+	assert.Eq(t, int32(2), atomic.LoadInt32(&calls))
+
+	// use the cached challenge
+	rx, err = httpreq.WrapResp(cli.Post("/data", "a=b"))
+	assert.NoErr(t, err)
+	assert.Eq(t, "ok, nc=00000002", rx.BodyString())
+	assert.Eq(t, int32(3), atomic.LoadInt32(&calls))
+
+	// wrong password
+	cli = httpreq.New(srv.URL).UseAuth(httpreq.NewDigestAuth("admin", "wrong")).DefaultHeader("X-Password", "pass")
+	resp, err := cli.Get("/data")
+	assert.NoErr(t, err)
+	assert.Eq(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestOAuth2Auth(t *testing.T) {
+	var tokenCalls int32
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&tokenCalls, 1)
+		id, secret, _ := r.BasicAuth()
+		if id != "cid" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_client"}`))
+			return
+		}
+
+		_ = r.ParseForm()
+		grant := r.PostForm.Get("grant_type")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token": "token-%d-%s", "token_type": "bearer", "expires_in": 3600, "refresh_token": "refresh-%d"}`, n, grant, n)
+	}))
+	defer tokenSrv.Close()
+
+	// the API server will reject the first token
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if auth == "" || auth == "Bearer token-1-client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(auth))
+	}))
+	defer apiSrv.Close()
+
+	var saved string
+	oa := httpreq.NewOAuth2Auth(tokenSrv.URL, "cid", "secret", func(c *httpreq.OAuth2Config) {
+		c.Scopes = []string{"read"}
+		c.OnToken = func(tk *httpreq.OAuth2Token) {
+			saved = tk.RefreshToken
+		}
+	})
+	cli := httpreq.New(apiSrv.URL).UseAuth(oa)
+
+	// 401 then refresh the token
+	rx, err := httpreq.WrapResp(cli.Get("/api"))
+	assert.NoErr(t, err)
+	assert.Eq(t, 200, rx.StatusCode)
+	assert.Eq(t, "Bearer token-2-refresh_token", rx.BodyString())
+	assert.Eq(t, "refresh-2", saved)
+
+	// use the cached token
+	rx, err = httpreq.WrapResp(cli.Get("/api"))
+	assert.NoErr(t, err)
+	assert.Eq(t, "Bearer token-2-refresh_token", rx.BodyString())
+	assert.Eq(t, int32(2), atomic.LoadInt32(&tokenCalls))
+
+	tk, err := oa.Token(context.Background())
+	assert.NoErr(t, err)
+	assert.True(t, tk.Valid())
+
+	// invalid client
+	cli = httpreq.New(apiSrv.URL).UseAuth(httpreq.NewOAuth2Auth(tokenSrv.URL, "cid", "wrong"))
+	_, err = cli.Get("/api")
+	var he *httpreq.HTTPError
+	assert.True(t, errors.As(err, &he))
+	assert.Eq(t, http.StatusUnauthorized, he.StatusCode)
+}
@@ -0,0 +1,163 @@
+package httpreq
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen error on the circuit breaker is open
+var ErrCircuitOpen = errors.New("httpreq: circuit breaker is open")
+
+// circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// BreakerConfig for CircuitBreaker
+type BreakerConfig struct {
+	// FailThreshold the consecutive failures to open the breaker. default is 5
+	FailThreshold int
+	// OpenTimeout the breaker keep open time, then allow a trial request(half-open). default is 30s
+	OpenTimeout time.Duration
+	// IsFailure check the request is failed. default: err != nil or status code >= 500
+	IsFailure func(resp *http.Response, err error) bool
+	// OnStateChange hook on the host breaker state changed
+	OnStateChange func(host, from, to string)
+}
+
+type hostBreaker struct {
+	state    string
+	failures int
+	openedAt time.Time
+}
+
+// CircuitBreaker stop send requests to a host for a while, after it continuously failed.
+//
+// Each host has its own breaker state:
+//
+//   - closed: allow all requests, will open after FailThreshold consecutive failures.
+//   - open: reject all requests with ErrCircuitOpen, will be half-open after OpenTimeout.
+//   - half-open: allow one trial request, will be closed if success, otherwise open again.
+type CircuitBreaker struct {
+	BreakerConfig
+	mu    sync.Mutex
+	hosts map[string]*hostBreaker
+}
+
+// NewCircuitBreaker create a circuit breaker
+func NewCircuitBreaker(fns ...func(bc *BreakerConfig)) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		BreakerConfig: BreakerConfig{
+			FailThreshold: 5,
+			OpenTimeout:   30 * time.Second,
+		},
+		hosts: make(map[string]*hostBreaker),
+	}
+
+	for _, fn := range fns {
+		fn(&cb.BreakerConfig)
+	}
+	return cb
+}
+
+// BreakerMiddleware create a circuit breaker middleware. see CircuitBreaker
+//
+// Usage:
+//
+//	cli.Use(httpreq.BreakerMiddleware(func(bc *httpreq.BreakerConfig) {
+//		bc.FailThreshold = 3
+//	}))
+func BreakerMiddleware(fns ...func(bc *BreakerConfig)) Middleware {
+	return NewCircuitBreaker(fns...).Middleware
+}
+
+// State get the breaker state of the host
+func (cb *CircuitBreaker) State(host string) string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if hb, ok := cb.hosts[host]; ok {
+		return hb.state
+	}
+	return BreakerClosed
+}
+
+// Middleware wrap the doer with the circuit breaker
+func (cb *CircuitBreaker) Middleware(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		host := req.URL.Host
+		if !cb.allow(host) {
+			return nil, ErrCircuitOpen
+		}
+
+		resp, err := next.Do(req)
+		cb.report(host, cb.isFailure(resp, err))
+		return resp, err
+	})
+}
+
+func (cb *CircuitBreaker) isFailure(resp *http.Response, err error) bool {
+	if cb.IsFailure != nil {
+		return cb.IsFailure(resp, err)
+	}
+	return err != nil || resp.StatusCode >= 500
+}
+
+// check the request to the host is allowed.
+func (cb *CircuitBreaker) allow(host string) bool {
+	cb.mu.Lock()
+	hb, ok := cb.hosts[host]
+	if !ok {
+		hb = &hostBreaker{state: BreakerClosed}
+		cb.hosts[host] = hb
+	}
+
+	from, allowed := hb.state, true
+	switch hb.state {
+	case BreakerOpen:
+		// allow one trial request after the open timeout
+		if allowed = time.Since(hb.openedAt) >= cb.OpenTimeout; allowed {
+			hb.state = BreakerHalfOpen
+		}
+	case BreakerHalfOpen: // the trial request is running
+		allowed = false
+	}
+
+	to := hb.state
+	cb.mu.Unlock()
+
+	cb.fireChange(host, from, to)
+	return allowed
+}
+
+// report the request result
+func (cb *CircuitBreaker) report(host string, failed bool) {
+	cb.mu.Lock()
+	hb := cb.hosts[host]
+	from := hb.state
+
+	if failed {
+		hb.failures++
+		if hb.state == BreakerHalfOpen || hb.failures >= cb.FailThreshold {
+			hb.state = BreakerOpen
+			hb.openedAt = time.Now()
+		}
+	} else {
+		hb.failures = 0
+		hb.state = BreakerClosed
+	}
+
+	to := hb.state
+	cb.mu.Unlock()
+
+	cb.fireChange(host, from, to)
+}
+
+func (cb *CircuitBreaker) fireChange(host, from, to string) {
+	if from != to && cb.OnStateChange != nil {
+		cb.OnStateChange(host, from, to)
+	}
+}
@@ -21,6 +21,9 @@ type Client struct {
 	// custom set default headers
 	headerMap map[string]string
 
+	// middlewares wrap the client doer. see Use()
+	middlewares []Middleware
+
 	// before send callback
 	beforeSend func(req *http.Request)
 	afterSend  AfterSendFn
@@ -110,6 +113,39 @@ func (h *Client) DefaultHeaderMap(kvMap map[string]string) *Client {
 	return h
 }
 
+// Use add middlewares to wrap the client doer. the first added is the outermost.
+//
+// Usage:
+//
+//	cli.Use(
+//		httpreq.RequestIDMiddleware("", nil),
+//		httpreq.RetryMiddleware(),
+//		httpreq.TimeoutMiddleware(3*time.Second), // timeout for each retry attempt
+//	)
+func (h *Client) Use(mws ...Middleware) *Client {
+	h.middlewares = append(h.middlewares, mws...)
+	return h
+}
+
+// UseAuth add an auth middleware for apply the auth info to each request. see AuthMiddleware()
+//
+// Usage:
+//
+//	cli.UseAuth(httpreq.BearerAuth("my-token"))
+func (h *Client) UseAuth(a Authenticator) *Client {
+	return h.Use(AuthMiddleware(a))
+}
+
+// SetCookieJar set cookie jar for the http client doer. eg: NewFileJar()
+//
+// NOTE: only works on the doer is *http.Client
+func (h *Client) SetCookieJar(jar http.CookieJar) *Client {
+	if hc, ok := h.client.(*http.Client); ok {
+		hc.Jar = jar
+	}
+	return h
+}
+
 // OnBeforeSend add callback before send.
 func (h *Client) OnBeforeSend(fn func(req *http.Request)) *Client {
 	h.beforeSend = fn
@@ -287,18 +323,34 @@ func (h *Client) sendRequest(req *http.Request, opt *Option) (*http.Response, er
 		}
 	}
 
-	cli := h // if timeout changed, create new client
-	if opt.Timeout > 0 && opt.Timeout != cli.timeout {
-		cli = NewClient(opt.Timeout)
+	// if timeout changed, use a copy of the http client. keep the cookie jar, transport and others.
+	doer := h.client
+	if hc, ok := doer.(*http.Client); ok && opt.Timeout > 0 && opt.Timeout != h.timeout {
+		hc2 := *hc
+		hc2.Timeout = time.Duration(opt.Timeout) * time.Millisecond
+		doer = &hc2
 	}
 
 	if h.beforeSend != nil {
 		h.beforeSend(req)
 	}
 
-	resp, err := cli.client.Do(req)
+	// log the request as curl command. will redact the auth info, and not read the non-rewindable body.
+	if opt.Logger != nil {
+		opt.Logger.Infof("send request: %s", toCurl(req, true))
+	}
+
+	resp, err := Chain(doer, h.middlewares...).Do(req)
 	if h.afterSend != nil {
 		h.afterSend(resp, err)
 	}
+
+	if opt.Logger != nil {
+		if err != nil {
+			opt.Logger.Errorf("request %s %s failed: %v", req.Method, req.URL.Redacted(), err)
+		} else {
+			opt.Logger.Infof("response status: %s", resp.Status)
+		}
+	}
 	return resp, err
 }
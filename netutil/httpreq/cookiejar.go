@@ -0,0 +1,130 @@
+package httpreq
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+)
+
+// jarEntry the cookie entry saved in the jar file
+type jarEntry struct {
+	// URL the cookie is set from. eg: https://example.com/path
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// FileJar a http.CookieJar can be persisted to a JSON file. see NewFileJar()
+//
+// The session cookies(without Expires and MaxAge) will also be saved.
+//
+// Usage:
+//
+//	jar, err := httpreq.NewFileJar("~/.myapp/cookies.json")
+//	cli := httpreq.New().SetCookieJar(jar)
+//	// ... send requests
+//	err = jar.Save()
+type FileJar struct {
+	File string
+
+	mu  sync.Mutex
+	jar *cookiejar.Jar
+	// key: URL + domain + path + name
+	entries map[string]*jarEntry
+}
+
+// NewFileJar create a FileJar, will load cookies from the file if exists.
+func NewFileJar(file string) (*FileJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fj := &FileJar{
+		File:    fsutil.ExpandPath(file),
+		jar:     jar,
+		entries: make(map[string]*jarEntry),
+	}
+	return fj, fj.load()
+}
+
+// SetCookies implements http.CookieJar
+func (fj *FileJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	fj.jar.SetCookies(u, cookies)
+
+	fj.mu.Lock()
+	defer fj.mu.Unlock()
+
+	origin := (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+	now := time.Now()
+	for _, c := range cookies {
+		nc := *c
+		key := origin + ";" + nc.Domain + ";" + nc.Path + ";" + nc.Name
+		if nc.MaxAge < 0 || (!nc.Expires.IsZero() && nc.Expires.Before(now)) {
+			delete(fj.entries, key)
+			continue
+		}
+
+		// convert MaxAge to Expires, for restore from the file.
+		if nc.MaxAge > 0 {
+			nc.Expires = now.Add(time.Duration(nc.MaxAge) * time.Second)
+			nc.MaxAge = 0
+		}
+		fj.entries[key] = &jarEntry{URL: origin + u.EscapedPath(), Cookie: &nc}
+	}
+}
+
+// Cookies implements http.CookieJar
+func (fj *FileJar) Cookies(u *url.URL) []*http.Cookie {
+	return fj.jar.Cookies(u)
+}
+
+// Save the not expired cookies to the file.
+func (fj *FileJar) Save() error {
+	fj.mu.Lock()
+	now := time.Now()
+	entries := make([]*jarEntry, 0, len(fj.entries))
+	for _, e := range fj.entries {
+		if e.Cookie.Expires.IsZero() || e.Cookie.Expires.After(now) {
+			entries = append(entries, e)
+		}
+	}
+	fj.mu.Unlock()
+
+	bs, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	// the cookies may contain the credentials, only the user can read.
+	return fsutil.AtomicWrite(fj.File, bs, fsutil.WithAtomicPerm(0600))
+}
+
+// load cookies from the file
+func (fj *FileJar) load() error {
+	bs, err := os.ReadFile(fj.File)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var entries []*jarEntry
+	if err := json.Unmarshal(bs, &entries); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		u, err := url.Parse(e.URL)
+		if err != nil || e.Cookie == nil {
+			continue
+		}
+		fj.SetCookies(u, []*http.Cookie{e.Cookie})
+	}
+	return nil
+}
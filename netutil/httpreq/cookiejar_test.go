@@ -0,0 +1,74 @@
+package httpreq_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestFileJar(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "abc", Path: "/", MaxAge: 3600})
+			http.SetCookie(w, &http.Cookie{Name: "lang", Value: "en", Path: "/"})
+		case "/logout":
+			http.SetCookie(w, &http.Cookie{Name: "lang", Value: "", Path: "/", MaxAge: -1})
+		}
+
+		var ss []string
+		for _, c := range r.Cookies() {
+			ss = append(ss, c.Name+"="+c.Value)
+		}
+		_, _ = w.Write([]byte(strings.Join(ss, ";")))
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "cookies.json")
+	jar, err := httpreq.NewFileJar(file)
+	assert.NoErr(t, err)
+
+	cli := httpreq.New(srv.URL).SetCookieJar(jar)
+	_, err = cli.Get("/login")
+	assert.NoErr(t, err)
+
+	rx, err := httpreq.WrapResp(cli.Get("/user"))
+	assert.NoErr(t, err)
+	assert.Eq(t, "sid=abc;lang=en", rx.BodyString())
+
+	// with custom timeout, should keep the cookie jar
+	rx, err = httpreq.WrapResp(cli.Get("/user", func(opt *httpreq.Option) {
+		opt.Timeout = 3000
+	}))
+	assert.NoErr(t, err)
+	assert.Eq(t, "sid=abc;lang=en", rx.BodyString())
+	assert.NoErr(t, jar.Save())
+	assert.True(t, fsutil.IsFile(file))
+
+	// load from the file
+	jar2, err := httpreq.NewFileJar(file)
+	assert.NoErr(t, err)
+	cli = httpreq.New(srv.URL).SetCookieJar(jar2)
+	rx, err = httpreq.WrapResp(cli.Get("/user"))
+	assert.NoErr(t, err)
+	assert.StrContains(t, rx.BodyString(), "sid=abc")
+	assert.StrContains(t, rx.BodyString(), "lang=en")
+
+	// delete cookie
+	_, err = cli.Get("/logout")
+	assert.NoErr(t, err)
+	assert.NoErr(t, jar2.Save())
+
+	jar3, err := httpreq.NewFileJar(file)
+	assert.NoErr(t, err)
+	cli = httpreq.New(srv.URL).SetCookieJar(jar3)
+	rx, err = httpreq.WrapResp(cli.Get("/user"))
+	assert.NoErr(t, err)
+	assert.Eq(t, "sid=abc", rx.BodyString())
+}
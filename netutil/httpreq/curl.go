@@ -0,0 +1,334 @@
+package httpreq
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gookit/goutil/arrutil"
+	"github.com/gookit/goutil/cliutil/cmdline"
+	"github.com/gookit/goutil/netutil/httpctype"
+)
+
+// ToCurl convert the request to a copy-pasteable curl command.
+//
+// NOTE: if the req.GetBody is nil, will read the body and reset it by a bytes reader.
+//
+// Usage:
+//
+//	req, _ := http.NewRequest("POST", "https://example.com/users", strings.NewReader(`{"name": "inhere"}`))
+//	req.Header.Set("Content-Type", "application/json")
+//	// curl -X POST -H 'Content-Type: application/json' -d '{"name": "inhere"}' https://example.com/users
+//	fmt.Println(httpreq.ToCurl(req))
+func ToCurl(req *http.Request) string { return toCurl(req, false) }
+
+// the header values will be redacted on log the request
+var logRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// forLog: for log the request. will redact the auth info and dont read the non-rewindable body,
+// use `--data-binary @-` instead.
+func toCurl(req *http.Request, forLog bool) string {
+	args := []string{"curl"}
+	if req.Method != "" && req.Method != http.MethodGet {
+		if req.Method == http.MethodHead {
+			args = append(args, "-I")
+		} else {
+			args = append(args, "-X", req.Method)
+		}
+	}
+
+	if u := req.URL.User; u != nil {
+		if forLog {
+			args = append(args, "-u", curlQuote(RedactedValue))
+		} else {
+			pwd, _ := u.Password()
+			args = append(args, "-u", curlQuote(u.Username()+":"+pwd))
+		}
+	}
+
+	keys := make([]string, 0, len(req.Header))
+	for key := range req.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, val := range req.Header[key] {
+			if forLog && arrutil.Contains(logRedactHeaders, key) {
+				args = append(args, "-H", curlQuote(key+": "+RedactedValue))
+				continue
+			}
+
+			// convert basic auth to -u user:pass
+			if key == "Authorization" && strings.HasPrefix(val, "Basic ") {
+				if bs, err := base64.StdEncoding.DecodeString(val[6:]); err == nil {
+					args = append(args, "-u", curlQuote(string(bs)))
+					continue
+				}
+			}
+			args = append(args, "-H", curlQuote(key+": "+val))
+		}
+	}
+
+	if body, ok := readReqBody(req, !forLog); ok {
+		if len(body) > 0 && body[0] == '@' {
+			args = append(args, "--data-raw", curlQuote(string(body)))
+		} else {
+			args = append(args, "-d", curlQuote(string(body)))
+		}
+	} else if req.Body != nil && req.Body != http.NoBody {
+		args = append(args, "--data-binary", "@-")
+	}
+
+	// remove the user info from URL
+	u := *req.URL
+	u.User = nil
+	args = append(args, curlQuote(u.String()))
+	return strings.Join(args, " ")
+}
+
+// read the request body and reset it. ok is false on no body or the body cannot be read.
+func readReqBody(req *http.Request, readBody bool) (body []byte, ok bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, false
+	}
+
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, false
+		}
+		defer rc.Close()
+
+		body, err = io.ReadAll(rc)
+		return body, err == nil
+	}
+
+	if !readBody {
+		return nil, false
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err == nil
+}
+
+// quote the curl argument. will use single quotes on contains the shell special chars.
+func curlQuote(s string) string {
+	if !strings.ContainsAny(s, "'\"$`\\!&|;<>()*?[]{}~#\n\t") {
+		return cmdline.Quote(s)
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// CurlCommand the parsed curl command. see ParseCurl()
+type CurlCommand struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   string
+}
+
+// curl options without value, will be ignored on parse
+var curlNoValueOpts = []string{
+	"-s", "--silent", "-S", "--show-error", "-k", "--insecure", "-L", "--location", "-v", "--verbose",
+	"-i", "--include", "--compressed", "-f", "--fail", "-#", "--progress-bar", "-N", "--no-buffer",
+}
+
+// curl options with value, will be ignored on parse
+var curlIgnoreOpts = []string{
+	"-m", "--max-time", "--connect-timeout", "-o", "--output", "--retry", "-w", "--write-out",
+	"-x", "--proxy", "--cacert", "--cert", "--key", "--resolve",
+}
+
+// ParseCurl parse a curl command line. support the common options:
+//
+//	-X, --request, -H, --header, -d, --data, --data-raw, --data-binary, --data-urlencode, --json,
+//	-u, --user, -A, --user-agent, -e, --referer, -b, --cookie, -I, --head, -G, --get, --url
+//
+// Usage:
+//
+//	cc, err := httpreq.ParseCurl(`curl -X POST -H 'Content-Type: application/json' -d '{"name": "inhere"}' https://example.com/users`)
+func ParseCurl(line string) (*CurlCommand, error) {
+	// remove line continuations
+	line = strings.NewReplacer("\\\r\n", " ", "\\\n", " ", "\r\n", " ", "\n", " ", "\t", " ").Replace(line)
+
+	args := cmdline.ParseLine(line)
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, errors.New("httpreq: invalid curl command, must start with 'curl'")
+	}
+
+	cc := &CurlCommand{Header: make(http.Header)}
+	var data []string
+	var useGet, isJSON bool
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "" {
+			continue
+		}
+
+		// not an option, as URL
+		if arg[0] != '-' || arg == "-" {
+			cc.URL = arg
+			continue
+		}
+
+		if arrutil.Contains(curlNoValueOpts, arg) {
+			continue
+		}
+
+		name, val := arg, ""
+		hasVal := false
+		// short option with value. eg: -XPOST
+		if len(arg) > 2 && arg[1] != '-' {
+			name, val, hasVal = arg[:2], arg[2:], true
+			// combined flags. eg: -sS
+			if arrutil.Contains(curlNoValueOpts, name) {
+				continue
+			}
+		}
+
+		switch name {
+		case "-I", "--head":
+			cc.Method = http.MethodHead
+			continue
+		case "-G", "--get":
+			useGet = true
+			continue
+		}
+
+		if !hasVal {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("httpreq: curl option %s requires a value", name)
+			}
+			i++
+			val = args[i]
+		}
+
+		switch name {
+		case "-X", "--request":
+			cc.Method = strings.ToUpper(val)
+		case "-H", "--header":
+			key, hv, ok := strings.Cut(val, ":")
+			if !ok {
+				return nil, fmt.Errorf("httpreq: invalid curl header %q", val)
+			}
+			cc.Header.Add(strings.TrimSpace(key), strings.TrimSpace(hv))
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii":
+			data = append(data, val)
+		case "--data-urlencode":
+			if key, dv, ok := strings.Cut(val, "="); ok {
+				data = append(data, key+"="+url.QueryEscape(dv))
+			} else {
+				data = append(data, url.QueryEscape(val))
+			}
+		case "--json":
+			isJSON = true
+			data = append(data, val)
+		case "-u", "--user":
+			cc.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(val)))
+		case "-A", "--user-agent":
+			cc.Header.Set("User-Agent", val)
+		case "-e", "--referer":
+			cc.Header.Set("Referer", val)
+		case "-b", "--cookie":
+			cc.Header.Add("Cookie", val)
+		case "--url":
+			cc.URL = val
+		default:
+			if !arrutil.Contains(curlIgnoreOpts, name) {
+				return nil, fmt.Errorf("httpreq: unsupported curl option %s", name)
+			}
+		}
+	}
+
+	if cc.URL == "" {
+		return nil, errors.New("httpreq: not found URL in curl command")
+	}
+
+	if len(data) > 0 {
+		if isJSON {
+			cc.Body = strings.Join(data, "")
+			setHeaderIfEmpty(cc.Header, httpctype.Key, httpctype.MIMEJSON)
+			setHeaderIfEmpty(cc.Header, "Accept", httpctype.MIMEJSON)
+		} else {
+			cc.Body = strings.Join(data, "&")
+		}
+
+		if useGet {
+			cc.URL = AppendQueryToURLString(cc.URL, MakeQuery(cc.Body))
+			cc.Body = ""
+		} else {
+			setHeaderIfEmpty(cc.Header, httpctype.Key, httpctype.MIMEForm)
+		}
+	}
+
+	if cc.Method == "" {
+		if cc.Body != "" {
+			cc.Method = http.MethodPost
+		} else {
+			cc.Method = http.MethodGet
+		}
+	}
+	return cc, nil
+}
+
+// Request create a http request from the curl command
+func (cc *CurlCommand) Request() (*http.Request, error) {
+	var body io.Reader
+	if cc.Body != "" {
+		body = strings.NewReader(cc.Body)
+	}
+
+	req, err := http.NewRequest(cc.Method, cc.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = cc.Header.Clone()
+	return req, nil
+}
+
+// Option create a request Option from the curl command. the URL is cc.URL
+//
+// Usage:
+//
+//	resp, err := cli.SendWithOpt(cc.URL, cc.Option())
+func (cc *CurlCommand) Option() *Option {
+	opt := &Option{Method: cc.Method}
+	for key, vals := range cc.Header {
+		// the multi Cookie headers join by "; ", others join by ", ". see RFC 7230 3.2.2
+		sep := ", "
+		if key == "Cookie" {
+			sep = "; "
+		}
+		opt.WithHeader(key, strings.Join(vals, sep))
+	}
+
+	if cc.Body != "" {
+		opt.StringBody(cc.Body)
+	}
+	return opt
+}
+
+// FromCurl parse a curl command line and create a http request. see ParseCurl()
+func FromCurl(line string) (*http.Request, error) {
+	cc, err := ParseCurl(line)
+	if err != nil {
+		return nil, err
+	}
+	return cc.Request()
+}
+
+func setHeaderIfEmpty(h http.Header, key, val string) {
+	if h.Get(key) == "" {
+		h.Set(key, val)
+	}
+}
@@ -0,0 +1,159 @@
+package httpreq_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestToCurl(t *testing.T) {
+	req, err := http.NewRequest("POST", "https://example.com/users?a=1&b=2", strings.NewReader(`{"name": "inhere"}`))
+	assert.NoErr(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Token", "abc")
+
+	line := httpreq.ToCurl(req)
+	assert.Eq(t, `curl -X POST -H "Content-Type: application/json" -H "X-Token: abc" -d '{"name": "inhere"}' 'https://example.com/users?a=1&b=2'`, line)
+
+	// body is readable after convert
+	bs, err := io.ReadAll(req.Body)
+	assert.NoErr(t, err)
+	assert.Eq(t, `{"name": "inhere"}`, string(bs))
+
+	// basic auth, single quote in body
+	req, err = http.NewRequest("PUT", "https://example.com/note", io.NopCloser(strings.NewReader(`it's`)))
+	assert.NoErr(t, err)
+	req.SetBasicAuth("admin", "pass")
+	assert.Eq(t, `curl -X PUT -u admin:pass -d 'it'\''s' https://example.com/note`, httpreq.ToCurl(req))
+
+	req, err = http.NewRequest("HEAD", "https://example.com", nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, `curl -I https://example.com`, httpreq.ToCurl(req))
+}
+
+func TestParseCurl(t *testing.T) {
+	cc, err := httpreq.ParseCurl(`curl -X POST -H 'Content-Type: application/json' \
+  -H "X-Token: abc" -sS --compressed \
+  -d '{"name": "inhere"}' 'https://example.com/users?a=1&b=2'`)
+	assert.NoErr(t, err)
+	assert.Eq(t, "POST", cc.Method)
+	assert.Eq(t, "https://example.com/users?a=1&b=2", cc.URL)
+	assert.Eq(t, "application/json", cc.Header.Get("Content-Type"))
+	assert.Eq(t, "abc", cc.Header.Get("X-Token"))
+	assert.Eq(t, `{"name": "inhere"}`, cc.Body)
+
+	// default method and content type
+	cc, err = httpreq.ParseCurl(`curl https://example.com/login -u admin:pass -d name=inhere -d age=23 -A my-agent`)
+	assert.NoErr(t, err)
+	assert.Eq(t, "POST", cc.Method)
+	assert.Eq(t, "name=inhere&age=23", cc.Body)
+	assert.Eq(t, "application/x-www-form-urlencoded", cc.Header.Get("Content-Type"))
+	assert.Eq(t, "my-agent", cc.Header.Get("User-Agent"))
+	assert.Eq(t, httpreq.BuildBasicAuth("admin", "pass"), cc.Header.Get("Authorization"))
+
+	// -G: data as query
+	cc, err = httpreq.ParseCurl(`curl -G -d name=inhere --url https://example.com/search`)
+	assert.NoErr(t, err)
+	assert.Eq(t, "GET", cc.Method)
+	assert.Eq(t, "https://example.com/search?name=inhere", cc.URL)
+	assert.Empty(t, cc.Body)
+
+	// errors
+	_, err = httpreq.ParseCurl(`wget https://example.com`)
+	assert.Err(t, err)
+	_, err = httpreq.ParseCurl(`curl -X GET`)
+	assert.ErrSubMsg(t, err, "not found URL")
+	_, err = httpreq.ParseCurl(`curl --unknown-opt val https://example.com`)
+	assert.ErrSubMsg(t, err, "unsupported curl option --unknown-opt")
+	_, err = httpreq.ParseCurl(`curl https://example.com -H`)
+	assert.ErrSubMsg(t, err, "requires a value")
+}
+
+func TestFromCurl(t *testing.T) {
+	req, err := http.NewRequest("POST", testSrvAddr+"/post", strings.NewReader(`{"name":"inhere"}`))
+	assert.NoErr(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	// round trip
+	req2, err := httpreq.FromCurl(httpreq.ToCurl(req))
+	assert.NoErr(t, err)
+	assert.Eq(t, "POST", req2.Method)
+	assert.Eq(t, req.URL.String(), req2.URL.String())
+	assert.Eq(t, "application/json", req2.Header.Get("Content-Type"))
+
+	resp, err := http.DefaultClient.Do(req2)
+	assert.NoErr(t, err)
+	rr := testutil.ParseRespToReply(resp)
+	assert.Eq(t, `{"name":"inhere"}`, rr.Body)
+
+	// send by option
+	cc, err := httpreq.ParseCurl(`curl -H 'X-Token: abc' ` + testSrvAddr + `/get?id=1`)
+	assert.NoErr(t, err)
+	resp, err = httpreq.New().SendWithOpt(cc.URL, cc.Option())
+	assert.NoErr(t, err)
+	rr = testutil.ParseRespToReply(resp)
+	assert.Eq(t, "GET", rr.Method)
+	assert.Eq(t, "abc", rr.Headers["X-Token"])
+
+	// repeated headers
+	cc, err = httpreq.ParseCurl(`curl -H 'Accept: text/html' -H 'Accept: application/json' -H 'Cookie: a=1' -H 'Cookie: b=2' https://example.com`)
+	assert.NoErr(t, err)
+	opt := cc.Option()
+	assert.Eq(t, "text/html, application/json", opt.HeaderMap["Accept"])
+	assert.Eq(t, "a=1; b=2", opt.HeaderMap["Cookie"])
+}
+
+type bufLogger struct {
+	bytes.Buffer
+}
+
+func (l *bufLogger) Infof(format string, args ...any) {
+	l.WriteString(fmt.Sprintf(format, args...) + "\n")
+}
+
+func (l *bufLogger) Errorf(format string, args ...any) {
+	l.WriteString("ERROR: " + fmt.Sprintf(format, args...) + "\n")
+}
+
+func TestOption_Logger(t *testing.T) {
+	lg := &bufLogger{}
+	_, err := httpreq.New(testSrvAddr).PostJSON("/post", map[string]string{"name": "inhere"}, func(opt *httpreq.Option) {
+		opt.Logger = lg
+	})
+	assert.NoErr(t, err)
+
+	str := lg.String()
+	assert.StrContains(t, str, "send request: curl -X POST -H ")
+	assert.StrContains(t, str, ` -d '{"name":"inhere"}`)
+	assert.StrContains(t, str, "' "+testSrvAddr+"/post")
+	assert.StrContains(t, str, "response status: 200 OK")
+
+	// redact the auth info on log
+	lg.Reset()
+	u := strings.Replace(testSrvAddr, "://", "://user:secret@", 1)
+	_, err = httpreq.New().Get(u+"/get", func(opt *httpreq.Option) {
+		opt.Logger = lg
+		opt.HeaderMap = map[string]string{
+			"Authorization": httpreq.BuildBasicAuth("admin", "pass"),
+			"Cookie":        "sid=abc",
+			"X-Trace":       "t1",
+		}
+	})
+	assert.NoErr(t, err)
+
+	str = lg.String()
+	assert.StrContains(t, str, "-H 'Authorization: [REDACTED]'")
+	assert.StrContains(t, str, "-H 'Cookie: [REDACTED]'")
+	assert.StrContains(t, str, `-H "X-Trace: t1"`)
+	assert.StrContains(t, str, "-u '[REDACTED]'")
+	assert.NotContains(t, str, "secret")
+	assert.NotContains(t, str, "sid=abc")
+	assert.NotContains(t, str, "admin")
+}
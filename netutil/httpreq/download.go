@@ -0,0 +1,208 @@
+package httpreq
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/x/encodes/hashutil"
+)
+
+// ProgressFn callback on download progress. total is -1 if unknown.
+type ProgressFn func(done, total int64)
+
+// DownloadOption for Client.Download()
+type DownloadOption struct {
+	// NoResume disable resume download from the exists temp file. default will resume by Range request.
+	NoResume bool
+	// Checksum for verify the downloaded file. format: "algo:hex", eg: "sha256:abcd...".
+	// if no algo prefix, default is sha256.
+	Checksum string
+	// Progress callback
+	Progress ProgressFn
+	// HeaderMap for the download request
+	HeaderMap map[string]string
+	// Context for the download request
+	Context context.Context
+}
+
+// DownloadOptFn option func for Client.Download()
+type DownloadOptFn func(opt *DownloadOption)
+
+// WithChecksum set checksum for verify the downloaded file. eg: "sha256:abcd..."
+func WithChecksum(checksum string) DownloadOptFn {
+	return func(opt *DownloadOption) { opt.Checksum = checksum }
+}
+
+// WithProgress set progress callback for download
+func WithProgress(fn ProgressFn) DownloadOptFn {
+	return func(opt *DownloadOption) { opt.Progress = fn }
+}
+
+// Download the URL to dstPath. will download to the temp file "dstPath.part" first,
+// and rename to dstPath on completed.
+//
+//   - resume download from the exists temp file by Range request.
+//   - verify the checksum if set, the temp file will be removed on mismatch.
+//
+// NOTE: the http.Client.Timeout includes reading the body, should use a client with long timeout
+// or set timeout by Context.
+//
+// Usage:
+//
+//	cli := httpreq.NewWithTimeout(0)
+//	err := cli.Download("https://example.com/app.tar.gz", "dist/app.tar.gz",
+//		httpreq.WithChecksum("sha256:abcd..."),
+//		httpreq.WithProgress(func(done, total int64) {
+//			fmt.Printf("\rdownloaded %d/%d", done, total)
+//		}),
+//	)
+func (h *Client) Download(url, dstPath string, fns ...DownloadOptFn) error {
+	opt := &DownloadOption{}
+	for _, fn := range fns {
+		fn(opt)
+	}
+
+	algo, expect := hashutil.AlgoSHA256, opt.Checksum
+	if before, after, ok := strings.Cut(opt.Checksum, ":"); ok {
+		algo, expect = strings.ToLower(before), after
+	}
+	// check the algo before send request
+	if expect != "" {
+		if _, err := hashutil.TryNewHash(algo); err != nil {
+			return err
+		}
+	}
+
+	tmpFile := dstPath + ".part"
+	if err := fsutil.MkParentDir(tmpFile); err != nil {
+		return err
+	}
+
+	var offset int64
+	if !opt.NoResume {
+		if fi, err := os.Stat(tmpFile); err == nil {
+			offset = fi.Size()
+		}
+	}
+
+	resp, err := h.Send(http.MethodGet, url, func(o *Option) {
+		o.Context = opt.Context
+		o.WithHeaderMap(opt.HeaderMap)
+		if offset > 0 {
+			o.WithHeader("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	total := int64(-1)
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		var start int64
+		start, total = parseContentRange(resp.Header.Get("Content-Range"))
+		if start != offset {
+			return fmt.Errorf("download %s failed, the response range start %d is not the offset %d", url, start, offset)
+		}
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the temp file is already completed
+		if offset > 0 {
+			return finishDownload(tmpFile, dstPath, algo, expect)
+		}
+		return fmt.Errorf("download %s failed, status: %s", url, resp.Status)
+	default:
+		if !IsSuccessful(resp.StatusCode) {
+			return fmt.Errorf("download %s failed, status: %s", url, resp.Status)
+		}
+		// server not support range, download from start.
+		offset = 0
+		if resp.ContentLength >= 0 {
+			total = resp.ContentLength
+		}
+	}
+
+	f, err := os.OpenFile(tmpFile, flag, fsutil.DefaultFilePerm)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = f
+	if opt.Progress != nil {
+		w = &progressWriter{w: f, done: offset, total: total, fn: opt.Progress}
+		opt.Progress(offset, total)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return finishDownload(tmpFile, dstPath, algo, expect)
+}
+
+// Download the URL to dstPath by default client. see Client.Download()
+func Download(url, dstPath string, fns ...DownloadOptFn) error {
+	return std.Download(url, dstPath, fns...)
+}
+
+// verify the checksum and rename the temp file to dstPath
+func finishDownload(tmpFile, dstPath, algo, expect string) error {
+	if expect != "" {
+		sum, err := fsutil.FileHash(tmpFile, algo)
+		if err != nil {
+			return err
+		}
+
+		if !strings.EqualFold(sum, expect) {
+			_ = os.Remove(tmpFile)
+			return fmt.Errorf("download checksum mismatch, expect %s:%s, got %s", algo, expect, sum)
+		}
+	}
+	return os.Rename(tmpFile, dstPath)
+}
+
+// parse the start offset and total size from Content-Range. eg: "bytes 100-199/200"
+//
+// returns -1 if the value is invalid or unknown. eg: "bytes 100-199/*"
+func parseContentRange(val string) (start, total int64) {
+	start, total = -1, -1
+	rng, size, ok := strings.Cut(strings.TrimPrefix(val, "bytes "), "/")
+	if !ok {
+		return
+	}
+
+	if n, err := strconv.ParseInt(size, 10, 64); err == nil {
+		total = n
+	}
+	if from, _, ok := strings.Cut(rng, "-"); ok {
+		if n, err := strconv.ParseInt(from, 10, 64); err == nil {
+			start = n
+		}
+	}
+	return
+}
+
+type progressWriter struct {
+	w     io.Writer
+	done  int64
+	total int64
+	fn    ProgressFn
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.done += int64(n)
+	pw.fn(pw.done, pw.total)
+	return n, err
+}
@@ -0,0 +1,146 @@
+package httpreq_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/goutil/x/assert"
+	"github.com/gookit/goutil/x/encodes/hashutil"
+)
+
+func TestOption_MultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	fPath := filepath.Join(dir, "app.txt")
+	assert.NoErr(t, os.WriteFile(fPath, []byte("file contents"), 0644))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		f, fh, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+
+		sum, _ := fsutil.ReaderHash(f, "md5")
+		_, _ = w.Write([]byte(r.FormValue("version") + "," + fh.Filename + "," + sum))
+	}))
+	defer srv.Close()
+
+	rx, err := httpreq.WrapResp(httpreq.New(srv.URL).WithOption().
+		MultipartBody(map[string]string{"version": "v1.0.0"}, map[string]string{"file": fPath}).
+		Send("POST", "/upload"))
+	assert.NoErr(t, err)
+	assert.Eq(t, 200, rx.StatusCode)
+	assert.Eq(t, "v1.0.0,app.txt,"+hashutil.MD5("file contents"), rx.BodyString())
+
+	// file not exists
+	_, err = httpreq.New(srv.URL).WithOption().
+		MultipartBody(nil, map[string]string{"file": filepath.Join(dir, "not-exists.txt")}).
+		Send("POST", "/upload")
+	assert.Err(t, err)
+}
+
+func TestClient_Download(t *testing.T) {
+	content := strings.Repeat("0123456789", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "app.bin", time.Now(), strings.NewReader(content))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "sub", "app.bin")
+	cli := httpreq.NewWithTimeout(5000)
+
+	var lastDone, lastTotal int64
+	err := cli.Download(srv.URL+"/app.bin", dst,
+		httpreq.WithChecksum("sha256:"+hashutil.Hash("sha256", content)),
+		httpreq.WithProgress(func(done, total int64) {
+			lastDone, lastTotal = done, total
+		}),
+	)
+	assert.NoErr(t, err)
+	assert.Eq(t, content, fsutil.ReadString(dst))
+	assert.Eq(t, int64(1000), lastDone)
+	assert.Eq(t, int64(1000), lastTotal)
+	assert.False(t, fsutil.IsFile(dst+".part"))
+
+	t.Run("resume", func(t *testing.T) {
+		dst := filepath.Join(dir, "resume.bin")
+		assert.NoErr(t, os.WriteFile(dst+".part", []byte(content[:300]), 0644))
+
+		var firstDone int64 = -1
+		err := cli.Download(srv.URL+"/app.bin", dst, httpreq.WithProgress(func(done, total int64) {
+			if firstDone < 0 {
+				firstDone = done
+			}
+			lastTotal = total
+		}))
+		assert.NoErr(t, err)
+		assert.Eq(t, content, fsutil.ReadString(dst))
+		assert.Eq(t, int64(300), firstDone)
+		assert.Eq(t, int64(1000), lastTotal)
+
+		// the temp file is completed
+		dst = filepath.Join(dir, "completed.bin")
+		assert.NoErr(t, os.WriteFile(dst+".part", []byte(content), 0644))
+		assert.NoErr(t, cli.Download(srv.URL+"/app.bin", dst))
+		assert.Eq(t, content, fsutil.ReadString(dst))
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		dst := filepath.Join(dir, "bad.bin")
+		err := cli.Download(srv.URL+"/app.bin", dst, httpreq.WithChecksum("abcd"))
+		assert.ErrSubMsg(t, err, "checksum mismatch")
+		assert.False(t, fsutil.IsFile(dst))
+		assert.False(t, fsutil.IsFile(dst+".part"))
+	})
+
+	t.Run("invalid algo", func(t *testing.T) {
+		var called bool
+		srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer srv2.Close()
+
+		err := cli.Download(srv2.URL+"/app.bin", filepath.Join(dir, "algo.bin"), httpreq.WithChecksum("sha3:abcd"))
+		assert.ErrSubMsg(t, err, "invalid hash algorithm")
+		assert.False(t, called)
+	})
+
+	t.Run("range start mismatch", func(t *testing.T) {
+		// always response the range from 100
+		srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Range", "bytes 100-999/1000")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(content[100:]))
+		}))
+		defer srv2.Close()
+
+		dst := filepath.Join(dir, "mismatch.bin")
+		assert.NoErr(t, os.WriteFile(dst+".part", []byte(content[:300]), 0644))
+		err := cli.Download(srv2.URL+"/app.bin", dst)
+		assert.ErrSubMsg(t, err, "range start 100 is not the offset 300")
+		assert.Eq(t, content[:300], fsutil.ReadString(dst+".part"))
+		assert.False(t, fsutil.IsFile(dst))
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		nf := httptest.NewServer(http.NotFoundHandler())
+		defer nf.Close()
+
+		err := cli.Download(nf.URL+"/app.bin", filepath.Join(dir, "nf.bin"))
+		assert.ErrSubMsg(t, err, "404")
+	})
+}
@@ -0,0 +1,161 @@
+package httpreq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gookit/goutil/netutil/httpctype"
+)
+
+// DefaultMaxRespSize default max response body size for the JSON helpers. 10MB
+var DefaultMaxRespSize int64 = 10 << 20
+
+// ErrRespTooLarge error on the response body exceeds the max size
+var ErrRespTooLarge = errors.New("httpreq: response body too large")
+
+// max body length in HTTPError.Error() message
+const errSnippetLen = 256
+
+// HTTPError error on the response status is not 2xx. returned by the JSON helpers.
+//
+// Usage:
+//
+//	var he *httpreq.HTTPError
+//	if errors.As(err, &he) && he.StatusCode == 404 {
+//		// ...
+//	}
+type HTTPError struct {
+	// Method and URL of the request
+	Method, URL string
+	// StatusCode and Status of the response
+	StatusCode int
+	Status     string
+	// Header of the response
+	Header http.Header
+	// Body data of the response, limited by the max response size.
+	Body []byte
+}
+
+// Error message, with the body snippet
+func (e *HTTPError) Error() string {
+	snippet := e.Body
+	if len(snippet) > errSnippetLen {
+		snippet = snippet[:errSnippetLen]
+	}
+	return fmt.Sprintf("httpreq: %s %s: status %s, body: %s", e.Method, e.URL, e.Status, bytes.TrimSpace(snippet))
+}
+
+// BindJSON decode the error body to a typed struct ptr.
+//
+// Usage:
+//
+//	var apiErr struct{ Code int; Message string }
+//	if he, ok := err.(*httpreq.HTTPError); ok {
+//		_ = he.BindJSON(&apiErr)
+//	}
+func (e *HTTPError) BindJSON(ptr any) error {
+	return json.Unmarshal(e.Body, ptr)
+}
+
+// MaxRespSize set the max response body size for the JSON helpers. see GetJSON, DoJSON
+func MaxRespSize(n int64) OptionFn {
+	return func(opt *Option) {
+		opt.MaxRespSize = n
+	}
+}
+
+// GetJSON send GET request and decode the JSON response body to T.
+// If cli is nil, will use the default client.
+//
+// Returns *HTTPError on the response status is not 2xx.
+//
+// Usage:
+//
+//	user, err := httpreq.GetJSON[User](cli, "/users/1")
+func GetJSON[T any](cli *Client, url string, optFns ...OptionFn) (T, error) {
+	var ret T
+	err := doJSON(cli, http.MethodGet, url, nil, &ret, optFns)
+	return ret, err
+}
+
+// DoJSON send request with JSON body data, and decode the JSON response body to Resp.
+// If cli is nil, will use the default client.
+//
+// Returns *HTTPError on the response status is not 2xx.
+//
+// Usage:
+//
+//	user, err := httpreq.DoJSON[*CreateUser, User](cli, "POST", "/users", &CreateUser{Name: "inhere"})
+func DoJSON[Req, Resp any](cli *Client, method, url string, data Req, optFns ...OptionFn) (Resp, error) {
+	var ret Resp
+	err := doJSON(cli, method, url, data, &ret, optFns)
+	return ret, err
+}
+
+func doJSON(cli *Client, method, url string, data, ptr any, optFns []OptionFn) error {
+	if cli == nil {
+		cli = std
+	}
+
+	opt := NewOption(optFns).WithMethod(method).WithHeader("Accept", httpctype.MIMEJSON)
+	if data != nil {
+		bs, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		opt.JSONBytesBody(bs)
+	}
+
+	resp, err := cli.SendWithOpt(url, opt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	maxSize := opt.MaxRespSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxRespSize
+	}
+
+	body, err := readLimited(resp.Body, maxSize)
+	if !IsSuccessful(resp.StatusCode) {
+		he := &HTTPError{
+			Method:     opt.Method,
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header,
+			Body:       body,
+		}
+		if resp.Request != nil {
+			he.URL = resp.Request.URL.String()
+		}
+		return he
+	}
+	if err != nil {
+		return err
+	}
+
+	// no content
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, ptr)
+}
+
+// read body up to maxSize, returns ErrRespTooLarge on exceeds.
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return body, err
+	}
+
+	if int64(len(body)) > maxSize {
+		return body[:maxSize], ErrRespTooLarge
+	}
+	return body, nil
+}
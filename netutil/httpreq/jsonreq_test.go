@@ -0,0 +1,109 @@
+package httpreq_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/goutil/x/assert"
+)
+
+type testUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newJSONServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/1":
+			_, _ = w.Write([]byte(`{"id": 1, "name": "inhere"}`))
+		case "/users":
+			var u testUser
+			if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code": 400, "message": "invalid body"}`))
+				return
+			}
+			u.ID = 2
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(u)
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/large":
+			_, _ = w.Write([]byte(`{"name": "` + strings.Repeat("a", 1024) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+		}
+	}))
+}
+
+func TestGetJSON(t *testing.T) {
+	srv := newJSONServer()
+	defer srv.Close()
+	cli := httpreq.New(srv.URL)
+
+	u, err := httpreq.GetJSON[testUser](cli, "/users/1")
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, u.ID)
+	assert.Eq(t, "inhere", u.Name)
+
+	mp, err := httpreq.GetJSON[map[string]any](cli, "/users/1")
+	assert.NoErr(t, err)
+	assert.Eq(t, "inhere", mp["name"])
+
+	// no content
+	u, err = httpreq.GetJSON[testUser](cli, "/empty")
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, u.ID)
+
+	// status error
+	_, err = httpreq.GetJSON[testUser](cli, "/users/404")
+	var he *httpreq.HTTPError
+	assert.True(t, errors.As(err, &he))
+	assert.Eq(t, 404, he.StatusCode)
+	assert.Eq(t, "application/json", he.Header.Get("Content-Type"))
+	assert.StrContains(t, he.Error(), "GET "+srv.URL+"/users/404: status 404 Not Found")
+	assert.StrContains(t, he.Error(), `"message": "not found"`)
+
+	apiErr := struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{}
+	assert.NoErr(t, he.BindJSON(&apiErr))
+	assert.Eq(t, 404, apiErr.Code)
+	assert.Eq(t, "not found", apiErr.Message)
+
+	// max response size
+	_, err = httpreq.GetJSON[testUser](cli, "/large", httpreq.MaxRespSize(512))
+	assert.True(t, errors.Is(err, httpreq.ErrRespTooLarge))
+	_, err = httpreq.GetJSON[testUser](cli, "/large")
+	assert.NoErr(t, err)
+}
+
+func TestDoJSON(t *testing.T) {
+	srv := newJSONServer()
+	defer srv.Close()
+	cli := httpreq.New(srv.URL)
+
+	u, err := httpreq.DoJSON[*testUser, testUser](cli, "POST", "/users", &testUser{Name: "tom"})
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, u.ID)
+	assert.Eq(t, "tom", u.Name)
+
+	_, err = httpreq.DoJSON[string, testUser](cli, "POST", "/users", "invalid")
+	he, ok := err.(*httpreq.HTTPError)
+	assert.True(t, ok)
+	assert.Eq(t, http.StatusBadRequest, he.StatusCode)
+	assert.Eq(t, "POST", he.Method)
+
+	// marshal error
+	_, err = httpreq.DoJSON[chan int, testUser](cli, "POST", "/users", make(chan int))
+	assert.Err(t, err)
+}
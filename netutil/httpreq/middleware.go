@@ -0,0 +1,88 @@
+package httpreq
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gookit/goutil/strutil"
+)
+
+// Middleware wrap the Doer, can handle the request before and after send.
+//
+// Usage:
+//
+//	func LogMiddleware(next httpreq.Doer) httpreq.Doer {
+//		return httpreq.DoerFunc(func(req *http.Request) (*http.Response, error) {
+//			resp, err := next.Do(req)
+//			log.Println(req.Method, req.URL, err)
+//			return resp, err
+//		})
+//	}
+type Middleware func(next Doer) Doer
+
+// Chain wrap the doer with middlewares. the first is the outermost.
+func Chain(d Doer, mws ...Middleware) Doer {
+	for i := len(mws) - 1; i >= 0; i-- {
+		d = mws[i](d)
+	}
+	return d
+}
+
+// DefaultRequestIDHeader default header name for RequestIDMiddleware
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware add request ID header to each request, if the header is not exists.
+//
+// header default is DefaultRequestIDHeader, genFn default generate a random 16 bytes hex string.
+func RequestIDMiddleware(header string, genFn func() string) Middleware {
+	header = strutil.OrElse(header, DefaultRequestIDHeader)
+	if genFn == nil {
+		genFn = func() string {
+			bs, _ := strutil.RandomBytes(16)
+			return hex.EncodeToString(bs)
+		}
+	}
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(header) == "" {
+				req = req.Clone(req.Context())
+				req.Header.Set(header, genFn())
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// TimeoutMiddleware set timeout for each request by context.
+// the timeout includes read the response body.
+func TimeoutMiddleware(timeout time.Duration) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			resp, err := next.Do(req.WithContext(ctx))
+			if err != nil || resp == nil || resp.Body == nil {
+				cancel()
+				return resp, err
+			}
+
+			// cancel the context on the body closed
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		})
+	}
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
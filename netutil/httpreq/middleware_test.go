@@ -0,0 +1,174 @@
+package httpreq_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func newStatusResp(code int, header ...string) *http.Response {
+	resp := &http.Response{
+		StatusCode: code,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("body")),
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		resp.Header.Set(header[i], header[i+1])
+	}
+	return resp
+}
+
+func TestClient_Use(t *testing.T) {
+	var order []string
+	mw := func(name string) httpreq.Middleware {
+		return func(next httpreq.Doer) httpreq.Doer {
+			return httpreq.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.Do(req)
+			})
+		}
+	}
+
+	cli := httpreq.New(testSrvAddr).Use(mw("a"), mw("b"), httpreq.RequestIDMiddleware("", nil))
+	resp, err := cli.Get("/get")
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"a", "b"}, order)
+
+	rr := testutil.ParseRespToReply(resp)
+	assert.NotEmpty(t, rr.Headers["X-Request-Id"])
+
+	// not override exists request ID
+	resp, err = cli.Get("/get", func(opt *httpreq.Option) {
+		opt.WithHeader("X-Request-ID", "my-req-id")
+	})
+	assert.NoErr(t, err)
+	rr = testutil.ParseRespToReply(resp)
+	assert.Eq(t, "my-req-id", rr.Headers["X-Request-Id"])
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	cli := httpreq.NewWithTimeout(3000).Use(httpreq.TimeoutMiddleware(50 * time.Millisecond))
+	_, err := cli.Get(srv.URL)
+	assert.Err(t, err)
+	assert.ErrIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	var calls int32
+	fail := httpreq.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if req.Body != nil {
+			bs, _ := io.ReadAll(req.Body)
+			assert.Eq(t, "data", string(bs))
+		}
+
+		switch n {
+		case 1:
+			return nil, errors.New("network error")
+		case 2:
+			return newStatusResp(503, "Retry-After", "0"), nil
+		}
+		return newStatusResp(200), nil
+	})
+
+	doer := httpreq.Chain(fail, httpreq.RetryMiddleware(httpreq.WithRetryWait(time.Millisecond, 10*time.Millisecond)))
+	req, _ := http.NewRequest("PUT", "http://example.com", strings.NewReader("data"))
+	resp, err := doer.Do(req)
+	assert.NoErr(t, err)
+	assert.Eq(t, 200, resp.StatusCode)
+	assert.Eq(t, int32(3), calls)
+
+	// max retries
+	calls = 0
+	doer = httpreq.Chain(httpreq.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return newStatusResp(502), nil
+	}), httpreq.RetryMiddleware(httpreq.WithMaxRetries(2), httpreq.WithRetryWait(time.Millisecond, time.Millisecond)))
+	resp, err = doer.Do(req)
+	assert.NoErr(t, err)
+	assert.Eq(t, 502, resp.StatusCode)
+	assert.Eq(t, int32(3), calls)
+
+	// not retry POST
+	calls = 0
+	req, _ = http.NewRequest("POST", "http://example.com", nil)
+	resp, _ = doer.Do(req)
+	assert.Eq(t, 502, resp.StatusCode)
+	assert.Eq(t, int32(1), calls)
+
+	// Retry-After too long
+	calls = 0
+	doer = httpreq.Chain(httpreq.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return newStatusResp(429, "Retry-After", "120"), nil
+	}), httpreq.RetryMiddleware())
+	req, _ = http.NewRequest("GET", "http://example.com", nil)
+	resp, _ = doer.Do(req)
+	assert.Eq(t, 429, resp.StatusCode)
+	assert.Eq(t, int32(1), calls)
+}
+
+func TestBreakerMiddleware(t *testing.T) {
+	var failing int32 = 1
+	doer := httpreq.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if atomic.LoadInt32(&failing) == 1 {
+			return newStatusResp(500), nil
+		}
+		return newStatusResp(200), nil
+	})
+
+	var changes []string
+	cb := httpreq.NewCircuitBreaker(func(bc *httpreq.BreakerConfig) {
+		bc.FailThreshold = 2
+		bc.OpenTimeout = 50 * time.Millisecond
+		bc.OnStateChange = func(host, from, to string) {
+			changes = append(changes, host+":"+to)
+		}
+	})
+	cli := httpreq.NewWithDoer(doer).Use(cb.Middleware)
+
+	for i := 0; i < 2; i++ {
+		resp, err := cli.Get("http://a.com/get")
+		assert.NoErr(t, err)
+		assert.Eq(t, 500, resp.StatusCode)
+	}
+	assert.Eq(t, httpreq.BreakerOpen, cb.State("a.com"))
+
+	_, err := cli.Get("http://a.com/get")
+	assert.ErrIs(t, err, httpreq.ErrCircuitOpen)
+	// other host is not affected
+	_, err = cli.Get("http://b.com/get")
+	assert.NoErr(t, err)
+
+	// half-open, trial failed
+	time.Sleep(60 * time.Millisecond)
+	_, err = cli.Get("http://a.com/get")
+	assert.NoErr(t, err)
+	assert.Eq(t, httpreq.BreakerOpen, cb.State("a.com"))
+
+	// half-open, trial success
+	time.Sleep(60 * time.Millisecond)
+	atomic.StoreInt32(&failing, 0)
+	_, err = cli.Get("http://a.com/get")
+	assert.NoErr(t, err)
+	assert.Eq(t, httpreq.BreakerClosed, cb.State("a.com"))
+	assert.Eq(t, []string{"a.com:open", "a.com:half-open", "a.com:open", "a.com:half-open", "a.com:closed"}, changes)
+}
@@ -0,0 +1,88 @@
+package httpreq
+
+import (
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// MultipartBody set multipart/form-data body with fields and files, the files are streamed without buffering.
+//
+//   - fields: form field name => value
+//   - files: form field name => file path
+//
+// NOTE: the body can only be sent once.
+//
+// Usage:
+//
+//	resp, err := cli.WithOption().
+//		MultipartBody(map[string]string{"version": "v1.0.0"}, map[string]string{"file": "dist/app.tar.gz"}).
+//		Send("POST", "/upload")
+func (o *Option) MultipartBody(fields, files map[string]string) *Option {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	o.ContentType = mw.FormDataContentType()
+	o.Body = &lazyReader{ReadCloser: pr, start: func() {
+		go func() {
+			_ = pw.CloseWithError(writeMultipart(mw, fields, files))
+		}()
+	}}
+	return o
+}
+
+// write the fields and files to multipart writer, in sorted order.
+func writeMultipart(mw *multipart.Writer, fields, files map[string]string) error {
+	for _, name := range sortedKeys(fields) {
+		if err := mw.WriteField(name, fields[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(files) {
+		if err := writeMultipartFile(mw, name, files[name]); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func writeMultipartFile(mw *multipart.Writer, field, fPath string) error {
+	f, err := os.Open(fPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := mw.CreateFormFile(field, filepath.Base(fPath))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func sortedKeys(mp map[string]string) []string {
+	keys := make([]string, 0, len(mp))
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lazyReader call start func on first read. avoid the goroutine leak if the body is never read.
+type lazyReader struct {
+	io.ReadCloser
+	once  sync.Once
+	start func()
+}
+
+func (r *lazyReader) Read(p []byte) (int, error) {
+	r.once.Do(r.start)
+	return r.ReadCloser.Read(p)
+}
@@ -19,6 +19,8 @@ type Option struct {
 	cli  *Client
 	sent bool
 	// Timeout for request. unit: ms
+	//
+	// NOTE: only works on the client doer is *http.Client, will use a copy of it with the timeout.
 	Timeout int
 	// Method for request
 	Method string
@@ -27,10 +29,12 @@ type Option struct {
 	// ContentType header
 	ContentType string
 
-	// Logger for request
+	// Logger for request. will log the request as curl command and the response status.
 	Logger ReqLogger
 	// Context for request
 	Context context.Context
+	// MaxRespSize max response body size for the JSON helpers. default is DefaultMaxRespSize
+	MaxRespSize int64
 
 	// Data for request. can be used on any request method.
 	//
@@ -0,0 +1,302 @@
+package httpreq
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gookit/goutil/fsutil"
+)
+
+// record modes for Recorder
+const (
+	// RecordAuto replay if the cassette file exists, otherwise record.
+	RecordAuto = "auto"
+	// RecordOnly always send the real request and record it.
+	RecordOnly = "record"
+	// ReplayOnly only replay from the cassette file, fail on unmatched request.
+	ReplayOnly = "replay"
+)
+
+// RedactedValue the value for replace the redacted headers
+const RedactedValue = "[REDACTED]"
+
+// ErrNoInteraction error on replay, not found the matched interaction in cassette
+var ErrNoInteraction = errors.New("httpreq: no matched interaction in cassette")
+
+// RecordedRequest the recorded request in cassette
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// BodyEncoding mark the body is base64 encoded, on it is not valid UTF-8.
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// RecordedResponse the recorded response in cassette
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// BodyEncoding mark the body is base64 encoded, on it is not valid UTF-8.
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// Interaction a recorded request-response exchange
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Cassette the recorded interactions, it is saved as a JSON file.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder a Doer for record the real exchanges to the cassette file, and replay them in tests.
+//
+// On replay, the request is matched by method, URL and body, the unused interactions are preferred.
+// will return ErrNoInteraction if not found matched interaction.
+//
+// Usage:
+//
+//	rec, err := httpreq.NewRecorder("testdata/users.cassette.json")
+//	cli := httpreq.NewWithDoer(rec)
+//	// first run will send real requests and record them, next runs will replay from the file.
+//	resp, err := cli.Get("https://api.example.com/users/1")
+type Recorder struct {
+	// File path of the cassette
+	File string
+	// Mode for record or replay. allow: RecordAuto(default), RecordOnly, ReplayOnly
+	Mode string
+	// Doer real doer for send request on record. default is http.DefaultClient
+	Doer Doer
+	// RedactHeaders the header values will be replaced to RedactedValue on record.
+	//
+	// default: Authorization, Proxy-Authorization, Cookie, Set-Cookie, X-Api-Key
+	RedactHeaders []string
+	// Redact custom redact the interaction on record. eg: remove token in URL query
+	//
+	// NOTE: if the URL or body is changed, should also set the Match func for replay.
+	Redact func(it *Interaction)
+	// Match custom check the request is matched the recorded request.
+	// default is match method, URL and body.
+	Match func(req *http.Request, body []byte, rr *RecordedRequest) bool
+
+	mu       sync.Mutex
+	replay   bool
+	used     []bool
+	cassette *Cassette
+}
+
+// NewRecorder create a Recorder with the cassette file, will load the file on replay.
+func NewRecorder(file string, fns ...func(r *Recorder)) (*Recorder, error) {
+	r := &Recorder{
+		File: file,
+		Mode: RecordAuto,
+		Doer: http.DefaultClient,
+		RedactHeaders: []string{
+			"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key",
+		},
+		cassette: &Cassette{},
+	}
+
+	for _, fn := range fns {
+		fn(r)
+	}
+
+	switch r.Mode {
+	case RecordAuto:
+		r.replay = fsutil.IsFile(file)
+	case ReplayOnly:
+		r.replay = true
+	case RecordOnly:
+	default:
+		return nil, fmt.Errorf("httpreq: invalid record mode %q", r.Mode)
+	}
+
+	if r.replay {
+		bs, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(bs, r.cassette); err != nil {
+			return nil, fmt.Errorf("httpreq: invalid cassette file %s: %w", file, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// IsReplay check is replay mode
+func (r *Recorder) IsReplay() bool { return r.replay }
+
+// Cassette get the recorded cassette
+func (r *Recorder) Cassette() *Cassette { return r.cassette }
+
+// Do send request on record, or find the matched response on replay.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		setRequestBody(req, body)
+	}
+
+	if r.replay {
+		return r.doReplay(req, body)
+	}
+	return r.doRecord(req, body)
+}
+
+func (r *Recorder) doReplay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := -1
+	for i, it := range r.cassette.Interactions {
+		if r.match(req, body, &it.Request) {
+			if !r.used[i] {
+				idx = i
+				break
+			}
+			// allow reuse the used interaction, if no more unused matched.
+			if idx < 0 {
+				idx = i
+			}
+		}
+	}
+
+	if idx < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.String())
+	}
+
+	r.used[idx] = true
+	rr := r.cassette.Interactions[idx].Response
+	respBody, err := decodeRecordBody(rr.Body, rr.BodyEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        rr.Status,
+		StatusCode:    rr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rr.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) doRecord(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.Doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	it := &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header.Clone(),
+		},
+	}
+	it.Request.Body, it.Request.BodyEncoding = encodeRecordBody(body)
+	it.Response.Body, it.Response.BodyEncoding = encodeRecordBody(respBody)
+	r.redact(it)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, it)
+	return resp, r.save()
+}
+
+// save the cassette to file. NOTE: must be called with lock
+func (r *Recorder) save() error {
+	bs, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.AtomicWrite(r.File, bs)
+}
+
+func (r *Recorder) redact(it *Interaction) {
+	for _, name := range r.RedactHeaders {
+		for _, h := range []http.Header{it.Request.Header, it.Response.Header} {
+			if vs := h.Values(name); len(vs) > 0 {
+				h.Del(name)
+				for range vs {
+					h.Add(name, RedactedValue)
+				}
+			}
+		}
+	}
+
+	if r.Redact != nil {
+		r.Redact(it)
+	}
+}
+
+func (r *Recorder) match(req *http.Request, body []byte, rr *RecordedRequest) bool {
+	if r.Match != nil {
+		return r.Match(req, body, rr)
+	}
+
+	if !strings.EqualFold(req.Method, rr.Method) || req.URL.String() != rr.URL {
+		return false
+	}
+
+	recBody, err := decodeRecordBody(rr.Body, rr.BodyEncoding)
+	return err == nil && bytes.Equal(body, recBody)
+}
+
+// set a re-readable body for the request
+func setRequestBody(req *http.Request, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+}
+
+func encodeRecordBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeRecordBody(body, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
@@ -0,0 +1,92 @@
+package httpreq_test
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := testutil.NewEchoServer()
+	file := filepath.Join(t.TempDir(), "echo.cassette.json")
+
+	// record
+	rec, err := httpreq.NewRecorder(file)
+	assert.NoErr(t, err)
+	assert.False(t, rec.IsReplay())
+
+	cli := httpreq.NewWithDoer(rec).BaseURL(srv.URL).DefaultHeader("Authorization", "Bearer secret")
+	resp, err := cli.Get("/get?id=1")
+	assert.NoErr(t, err)
+	rr := testutil.ParseRespToReply(resp)
+	assert.Eq(t, "1", rr.Query["id"])
+
+	resp, err = cli.PostJSON("/post", map[string]any{"name": "inhere"})
+	assert.NoErr(t, err)
+	rr = testutil.ParseRespToReply(resp)
+	assert.Eq(t, `{"name":"inhere"}`+"\n", rr.Body)
+	assert.Len(t, rec.Cassette().Interactions, 2)
+
+	// header is redacted
+	assert.True(t, fsutil.IsFile(file))
+	assert.StrContains(t, fsutil.ReadString(file), `"Authorization": [
+            "[REDACTED]"
+          ]`)
+	assert.Eq(t, httpreq.RedactedValue, rec.Cassette().Interactions[0].Request.Header.Get("Authorization"))
+
+	// replay, the server is closed
+	srv.Close()
+	rec, err = httpreq.NewRecorder(file)
+	assert.NoErr(t, err)
+	assert.True(t, rec.IsReplay())
+
+	cli = httpreq.NewWithDoer(rec).BaseURL(srv.URL)
+	resp, err = cli.PostJSON("/post", map[string]any{"name": "inhere"})
+	assert.NoErr(t, err)
+	rr = testutil.ParseRespToReply(resp)
+	assert.Eq(t, `{"name":"inhere"}`+"\n", rr.Body)
+
+	rx, err := httpreq.WrapResp(cli.Get("/get?id=1"))
+	assert.NoErr(t, err)
+	assert.Eq(t, 200, rx.StatusCode)
+	assert.StrContains(t, rx.BodyString(), `"id": "1"`)
+
+	// unmatched: query and body are different
+	_, err = cli.Get("/get?id=2")
+	assert.True(t, errors.Is(err, httpreq.ErrNoInteraction))
+	_, err = cli.PostJSON("/post", map[string]any{"name": "tom"})
+	assert.ErrSubMsg(t, err, "POST "+srv.URL+"/post")
+}
+
+func TestNewRecorder_error(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "not-exists.json")
+	_, err := httpreq.NewRecorder(file, func(r *httpreq.Recorder) {
+		r.Mode = httpreq.ReplayOnly
+	})
+	assert.Err(t, err)
+
+	_, err = httpreq.NewRecorder(file, func(r *httpreq.Recorder) {
+		r.Mode = "invalid"
+	})
+	assert.ErrSubMsg(t, err, "invalid record mode")
+
+	// custom doer on record only mode
+	rec, err := httpreq.NewRecorder(file, func(r *httpreq.Recorder) {
+		r.Mode = httpreq.RecordOnly
+		r.Doer = httpreq.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return newStatusResp(201, "Set-Cookie", "sid=abc"), nil
+		})
+	})
+	assert.NoErr(t, err)
+
+	resp, err := httpreq.NewWithDoer(rec).Get("http://example.com/create")
+	assert.NoErr(t, err)
+	assert.Eq(t, 201, resp.StatusCode)
+	assert.Eq(t, httpreq.RedactedValue, rec.Cassette().Interactions[0].Response.Header.Get("Set-Cookie"))
+}
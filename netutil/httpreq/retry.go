@@ -0,0 +1,171 @@
+package httpreq
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gookit/goutil/arrutil"
+)
+
+// RetryConfig for RetryMiddleware
+type RetryConfig struct {
+	// MaxRetries max retry times. default is 3
+	MaxRetries int
+	// MinWait, MaxWait the wait time between retries, it is doubled on each retry.
+	//
+	// default is 200ms and 5s.
+	MinWait, MaxWait time.Duration
+	// Methods allow to retry. default is the idempotent methods: GET, HEAD, OPTIONS, TRACE, PUT, DELETE
+	Methods []string
+	// StatusCodes need to retry. default is 429, 502, 503, 504
+	StatusCodes []int
+	// RetryIf custom check need to retry. will override the StatusCodes check.
+	RetryIf func(resp *http.Response, err error) bool
+}
+
+// RetryFn option func for RetryConfig
+type RetryFn func(rc *RetryConfig)
+
+// WithMaxRetries set max retry times
+func WithMaxRetries(n int) RetryFn {
+	return func(rc *RetryConfig) { rc.MaxRetries = n }
+}
+
+// WithRetryWait set min and max wait time between retries
+func WithRetryWait(minWait, maxWait time.Duration) RetryFn {
+	return func(rc *RetryConfig) {
+		rc.MinWait, rc.MaxWait = minWait, maxWait
+	}
+}
+
+// NewRetryConfig create a RetryConfig with default settings
+func NewRetryConfig(fns ...RetryFn) *RetryConfig {
+	rc := &RetryConfig{
+		MaxRetries:  3,
+		MinWait:     200 * time.Millisecond,
+		MaxWait:     5 * time.Second,
+		Methods:     []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete},
+		StatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	}
+
+	for _, fn := range fns {
+		fn(rc)
+	}
+	return rc
+}
+
+// RetryMiddleware retry the request on network error or the status code is in StatusCodes,
+// with exponential backoff. will respect the `Retry-After` header in response.
+//
+// Only retry the idempotent methods, and the request body must be re-readable(req.GetBody is not nil).
+//
+// Usage:
+//
+//	cli.Use(httpreq.RetryMiddleware(httpreq.WithMaxRetries(5)))
+func RetryMiddleware(fns ...RetryFn) Middleware {
+	rc := NewRetryConfig(fns...)
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if !rc.canRetry(req) {
+				return next.Do(req)
+			}
+
+			wait := rc.MinWait
+			for i := 0; ; i++ {
+				resp, err := next.Do(req)
+				if i >= rc.MaxRetries || !rc.needRetry(resp, err) {
+					return resp, err
+				}
+
+				delay := wait
+				if resp != nil {
+					if ra, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+						// too long to wait, return the response
+						if ra > rc.MaxWait {
+							return resp, err
+						}
+						delay = ra
+					}
+
+					// drain and close the body for reuse connection
+					_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+					_ = resp.Body.Close()
+				}
+
+				timer := time.NewTimer(delay)
+				select {
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				case <-timer.C:
+				}
+
+				if req, err = rewindRequest(req); err != nil {
+					return nil, err
+				}
+				if wait *= 2; wait > rc.MaxWait {
+					wait = rc.MaxWait
+				}
+			}
+		})
+	}
+}
+
+func (rc *RetryConfig) canRetry(req *http.Request) bool {
+	if rc.MaxRetries <= 0 || !arrutil.Contains(rc.Methods, req.Method) {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func (rc *RetryConfig) needRetry(resp *http.Response, err error) bool {
+	if rc.RetryIf != nil {
+		return rc.RetryIf(resp, err)
+	}
+	if err != nil {
+		return true
+	}
+	return arrutil.Contains(rc.StatusCodes, resp.StatusCode)
+}
+
+// reset the request body for resend
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	nr := req.Clone(req.Context())
+	nr.Body = body
+	return nr, nil
+}
+
+// parse Retry-After header value. allow: seconds, HTTP date
+func parseRetryAfter(val string) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+
+	if sec, err := strconv.Atoi(val); err == nil {
+		if sec < 0 {
+			return 0, false
+		}
+		return time.Duration(sec) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(val); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
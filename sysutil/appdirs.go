@@ -0,0 +1,217 @@
+package sysutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// AppPaths the standard dirs for an application. see AppDirs()
+type AppPaths struct {
+	// Name of the application
+	Name string
+	// Legacy mark use the legacy dir `~/.appname`
+	Legacy bool
+
+	Config  string
+	Data    string
+	Cache   string
+	State   string
+	Runtime string
+	Log     string
+
+	// ConfigDirs for search config files, the first is Config. see FindConfig()
+	//
+	// On Linux, will append the dirs in `XDG_CONFIG_DIRS`(default: /etc/xdg)
+	ConfigDirs []string
+	// DataDirs for search data files, the first is Data.
+	//
+	// On Linux, will append the dirs in `XDG_DATA_DIRS`(default: /usr/local/share:/usr/share)
+	DataDirs []string
+}
+
+// AppDirs get the standard dirs for the application.
+//
+// On Linux(and other unix), follow the XDG base directory spec:
+//
+//	Config:  $XDG_CONFIG_HOME/appname, default ~/.config/appname
+//	Data:    $XDG_DATA_HOME/appname, default ~/.local/share/appname
+//	Cache:   $XDG_CACHE_HOME/appname, default ~/.cache/appname
+//	State:   $XDG_STATE_HOME/appname, default ~/.local/state/appname
+//	Runtime: $XDG_RUNTIME_DIR/appname, default $TMPDIR/appname-UID
+//	Log:     State/log
+//
+// If the legacy dir `~/.appname` exists, will use it as base dir for compatible.
+// but the XDG_CONFIG_HOME is set or the Config dir exists, will not use the legacy dir.
+//
+// On macOS and Windows, will use the platform dirs if the XDG_* env is not set.
+//
+// Usage:
+//
+//	dirs := sysutil.AppDirs("myapp")
+//	if err := dirs.Ensure(); err != nil {
+//		return err
+//	}
+//	cfgFile := dirs.FindConfig("config.toml")
+func AppDirs(appName string) *AppPaths {
+	home := UserHomeDir()
+	ap := &AppPaths{Name: appName}
+
+	var cfgBase, dataBase, cacheBase, stateBase string
+	switch runtime.GOOS {
+	case "darwin":
+		appSupport := filepath.Join(home, "Library", "Application Support")
+		cfgBase, dataBase, stateBase = appSupport, appSupport, appSupport
+		cacheBase = filepath.Join(home, "Library", "Caches")
+	case "windows":
+		cfgBase = envOr("APPDATA", filepath.Join(home, "AppData", "Roaming"))
+		dataBase = envOr("LOCALAPPDATA", filepath.Join(home, "AppData", "Local"))
+		cacheBase, stateBase = dataBase, dataBase
+	default:
+		cfgBase = filepath.Join(home, ".config")
+		dataBase = filepath.Join(home, ".local", "share")
+		cacheBase = filepath.Join(home, ".cache")
+		stateBase = filepath.Join(home, ".local", "state")
+	}
+
+	ap.Config = filepath.Join(envOr("XDG_CONFIG_HOME", cfgBase), appName)
+
+	// legacy dir: ~/.appname. only use it on the XDG_CONFIG_HOME is not set and the config dir not exists.
+	if os.Getenv("XDG_CONFIG_HOME") == "" && !isDir(ap.Config) {
+		if legacy := filepath.Join(home, "."+appName); isDir(legacy) {
+			ap.Legacy = true
+			ap.Config, ap.Data, ap.State = legacy, legacy, legacy
+			ap.Cache = filepath.Join(legacy, "cache")
+			ap.Log = filepath.Join(legacy, "log")
+			ap.Runtime = appRuntimeDir(appName)
+			ap.ConfigDirs = []string{ap.Config}
+			ap.DataDirs = []string{ap.Data}
+			return ap
+		}
+	}
+
+	ap.Data = filepath.Join(envOr("XDG_DATA_HOME", dataBase), appName)
+	ap.Cache = filepath.Join(envOr("XDG_CACHE_HOME", cacheBase), appName)
+	ap.State = filepath.Join(envOr("XDG_STATE_HOME", stateBase), appName)
+	ap.Runtime = appRuntimeDir(appName)
+
+	if runtime.GOOS == "darwin" && os.Getenv("XDG_STATE_HOME") == "" {
+		ap.Log = filepath.Join(home, "Library", "Logs", appName)
+	} else {
+		ap.Log = filepath.Join(ap.State, "log")
+	}
+
+	// on Windows, the Data, Cache, State are in the same base dir
+	if runtime.GOOS == "windows" {
+		if ap.Cache == ap.Data {
+			ap.Cache = filepath.Join(ap.Data, "cache")
+		}
+		if ap.State == ap.Data {
+			ap.State = filepath.Join(ap.Data, "state")
+			ap.Log = filepath.Join(ap.State, "log")
+		}
+	}
+
+	ap.ConfigDirs = []string{ap.Config}
+	ap.DataDirs = []string{ap.Data}
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		for _, dir := range filepath.SplitList(envOr("XDG_CONFIG_DIRS", "/etc/xdg")) {
+			ap.ConfigDirs = append(ap.ConfigDirs, filepath.Join(dir, appName))
+		}
+		for _, dir := range filepath.SplitList(envOr("XDG_DATA_DIRS", "/usr/local/share:/usr/share")) {
+			ap.DataDirs = append(ap.DataDirs, filepath.Join(dir, appName))
+		}
+	}
+	return ap
+}
+
+// Ensure create the Config, Data, Cache, State, Runtime and Log dirs if not exists.
+//
+// The Runtime dir is created with mode 0700, others are 0755. will return error if
+// the Runtime dir is a symlink, owned by other user or accessible by other users.
+func (ap *AppPaths) Ensure() error {
+	for _, dir := range []string{ap.Config, ap.Data, ap.Cache, ap.State, ap.Log} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return ensurePrivateDir(ap.Runtime)
+}
+
+// ConfigFile get file path in the Config dir
+func (ap *AppPaths) ConfigFile(name string) string { return filepath.Join(ap.Config, name) }
+
+// DataFile get file path in the Data dir
+func (ap *AppPaths) DataFile(name string) string { return filepath.Join(ap.Data, name) }
+
+// CacheFile get file path in the Cache dir
+func (ap *AppPaths) CacheFile(name string) string { return filepath.Join(ap.Cache, name) }
+
+// LogFile get file path in the Log dir
+func (ap *AppPaths) LogFile(name string) string { return filepath.Join(ap.Log, name) }
+
+// FindConfig search the config file in ConfigDirs, returns the first exists file path.
+// returns empty string if not found.
+func (ap *AppPaths) FindConfig(name string) string { return findInDirs(ap.ConfigDirs, name) }
+
+// FindData search the data file in DataDirs, returns the first exists file path.
+// returns empty string if not found.
+func (ap *AppPaths) FindData(name string) string { return findInDirs(ap.DataDirs, name) }
+
+func findInDirs(dirs []string, name string) string {
+	for _, dir := range dirs {
+		fPath := filepath.Join(dir, name)
+		if fi, err := os.Stat(fPath); err == nil && !fi.IsDir() {
+			return fPath
+		}
+	}
+	return ""
+}
+
+func appRuntimeDir(appName string) string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, appName)
+	}
+
+	// fallback to temp dir, add the uid for avoid conflict with other users.
+	if uid := os.Getuid(); uid >= 0 {
+		return filepath.Join(os.TempDir(), appName+"-"+strconv.Itoa(uid))
+	}
+	return filepath.Join(os.TempDir(), appName)
+}
+
+// ensurePrivateDir create the dir with mode 0700 if not exists, and check it is safe for use.
+// the dir may be in the shared temp dir, other users can pre-create it.
+func ensurePrivateDir(dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("sysutil: the dir %s is a symlink or not a dir", dir)
+	}
+	return checkPrivateDir(dir, fi)
+}
+
+func isDir(dir string) bool {
+	fi, err := os.Stat(dir)
+	return err == nil && fi.IsDir()
+}
+
+func envOr(name, def string) string {
+	// XDG spec: relative paths should be ignored
+	if val := os.Getenv(name); val != "" && (filepath.IsAbs(val) || !strings.HasPrefix(name, "XDG_")) {
+		return val
+	}
+	return def
+}
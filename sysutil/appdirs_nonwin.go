@@ -0,0 +1,20 @@
+//go:build !windows
+
+package sysutil
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivateDir check the dir is owned by current user, and not accessible by the group and others.
+func checkPrivateDir(dir string, fi os.FileInfo) error {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("sysutil: the dir %s is owned by other user(uid: %d)", dir, st.Uid)
+	}
+	if perm := fi.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("sysutil: the dir %s is accessible by other users(mode: %s)", dir, perm)
+	}
+	return nil
+}
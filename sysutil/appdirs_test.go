@@ -0,0 +1,123 @@
+package sysutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/gookit/goutil/sysutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestAppDirs(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("the XDG dirs test only on Linux")
+		return
+	}
+
+	home := sysutil.UserHomeDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_CONFIG_DIRS", "")
+	t.Setenv("XDG_DATA_DIRS", "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+
+	ap := sysutil.AppDirs("goutil-test-app")
+	assert.False(t, ap.Legacy)
+	assert.Eq(t, filepath.Join(home, ".config/goutil-test-app"), ap.Config)
+	assert.Eq(t, filepath.Join(home, ".local/share/goutil-test-app"), ap.Data)
+	assert.Eq(t, filepath.Join(home, ".cache/goutil-test-app"), ap.Cache)
+	assert.Eq(t, filepath.Join(home, ".local/state/goutil-test-app"), ap.State)
+	assert.Eq(t, filepath.Join(ap.State, "log"), ap.Log)
+	assert.StrContains(t, ap.Runtime, "goutil-test-app-")
+	assert.Eq(t, []string{ap.Config, "/etc/xdg/goutil-test-app"}, ap.ConfigDirs)
+	assert.Len(t, ap.DataDirs, 3)
+
+	// with XDG env
+	base := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(base, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(base, "data"))
+	t.Setenv("XDG_CACHE_HOME", "relative/cache") // relative path will be ignored
+	t.Setenv("XDG_STATE_HOME", filepath.Join(base, "state"))
+	t.Setenv("XDG_RUNTIME_DIR", filepath.Join(base, "run"))
+	t.Setenv("XDG_CONFIG_DIRS", filepath.Join(base, "etc1")+":"+filepath.Join(base, "etc2"))
+
+	ap = sysutil.AppDirs("myapp")
+	assert.Eq(t, filepath.Join(base, "config/myapp"), ap.Config)
+	assert.Eq(t, filepath.Join(base, "data/myapp"), ap.Data)
+	assert.Eq(t, filepath.Join(home, ".cache/myapp"), ap.Cache)
+	assert.Eq(t, filepath.Join(base, "state/myapp/log"), ap.Log)
+	assert.Eq(t, filepath.Join(base, "run/myapp"), ap.Runtime)
+	assert.Eq(t, filepath.Join(base, "config/myapp/app.toml"), ap.ConfigFile("app.toml"))
+	assert.Len(t, ap.ConfigDirs, 3)
+
+	// ensure dirs
+	ap.Cache = filepath.Join(base, "cache/myapp")
+	assert.NoErr(t, ap.Ensure())
+	for _, dir := range []string{ap.Config, ap.Data, ap.Cache, ap.State, ap.Log} {
+		assert.DirExists(t, dir)
+	}
+	fi, err := os.Stat(ap.Runtime)
+	assert.NoErr(t, err)
+	assert.Eq(t, os.FileMode(0700), fi.Mode().Perm())
+
+	// unsafe runtime dir
+	assert.NoErr(t, os.Chmod(ap.Runtime, 0755))
+	assert.ErrSubMsg(t, ap.Ensure(), "accessible by other users")
+	assert.NoErr(t, os.Remove(ap.Runtime))
+	assert.NoErr(t, os.Symlink(base, ap.Runtime))
+	assert.ErrSubMsg(t, ap.Ensure(), "is a symlink")
+	assert.NoErr(t, os.Remove(ap.Runtime))
+	assert.NoErr(t, ap.Ensure())
+
+	// find config
+	assert.Empty(t, ap.FindConfig("app.toml"))
+	etc2File := filepath.Join(base, "etc2/myapp/app.toml")
+	assert.NoErr(t, os.MkdirAll(filepath.Dir(etc2File), 0755))
+	assert.NoErr(t, os.WriteFile(etc2File, []byte("name = 'etc2'"), 0644))
+	assert.Eq(t, etc2File, ap.FindConfig("app.toml"))
+
+	assert.NoErr(t, os.WriteFile(ap.ConfigFile("app.toml"), []byte("name = 'user'"), 0644))
+	assert.Eq(t, ap.ConfigFile("app.toml"), ap.FindConfig("app.toml"))
+	assert.Empty(t, ap.FindData("not-exists.db"))
+}
+
+func TestAppDirs_legacy(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("the XDG dirs test only on Linux")
+		return
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	home := sysutil.UserHomeDir()
+	name := "goutil-test-legacy-" + strconv.Itoa(os.Getpid())
+	legacy := filepath.Join(home, "."+name)
+	xdgDir := filepath.Join(home, ".config", name)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(legacy)
+		_ = os.RemoveAll(xdgDir)
+	})
+
+	// only the legacy dir exists
+	assert.NoErr(t, os.MkdirAll(legacy, 0755))
+	ap := sysutil.AppDirs(name)
+	assert.True(t, ap.Legacy)
+	assert.Eq(t, legacy, ap.Config)
+	assert.Eq(t, filepath.Join(legacy, "cache"), ap.Cache)
+
+	// the XDG_CONFIG_HOME is set
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	ap = sysutil.AppDirs(name)
+	assert.False(t, ap.Legacy)
+
+	// both the legacy and XDG config dir exist
+	t.Setenv("XDG_CONFIG_HOME", "")
+	assert.NoErr(t, os.MkdirAll(xdgDir, 0755))
+	ap = sysutil.AppDirs(name)
+	assert.False(t, ap.Legacy)
+	assert.Eq(t, xdgDir, ap.Config)
+}
@@ -0,0 +1,8 @@
+package sysutil
+
+import "os"
+
+// checkPrivateDir on windows is not supported, the access is controlled by ACL.
+func checkPrivateDir(_ string, _ os.FileInfo) error {
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/gookit/goutil"
 	"github.com/gookit/goutil/arrutil"
@@ -30,6 +31,30 @@ type Cmd struct {
 	BeforeRun func(c *Cmd)
 	// AfterRun hook
 	AfterRun func(c *Cmd, err error)
+
+	// Timeout for run the command. 0 for no limit.
+	//
+	// On timeout, will send SIGTERM first, then send SIGKILL after GracePeriod.
+	Timeout time.Duration
+	// GracePeriod wait for exit after send SIGTERM. default is DefaultGracePeriod
+	GracePeriod time.Duration
+	// NewGroup run the command in a new process group, the whole process tree will be killed.
+	NewGroup bool
+	// MaxOutput max bytes to capture stdout and stderr for Execute(). default is DefaultMaxOutput
+	MaxOutput int
+	// PTY run the command in a pseudo-terminal. see WithPTY()
+	PTY bool
+	// Transcript record the PTY session output. see WithTranscript()
+	Transcript io.Writer
+
+	// redirects for stdin, stdout, stderr. will be applied on run.
+	redirects []redirectFn
+	// context for run command. will terminate the process on done.
+	ctx context.Context
+	// watcher for kill the process on timeout or context done
+	watch *killWatcher
+	// the running PTY session
+	pty *ptySession
 }
 
 // NewGitCmd instance
@@ -52,11 +77,14 @@ func NewCmd(bin string, args ...string) *Cmd {
 	return WrapGoCmd(exec.Command(bin, args...))
 }
 
-// CmdWithCtx create new instance with context.
+// CmdWithCtx create new instance with context. see Cmd.WithContext
+//
+// When the context done, will send SIGTERM to the process first, then send SIGKILL after
+// the grace period. and the run methods will return the ctx.Err() instead of the exit error.
 //
-// see exec.CommandContext
+// NOTE: the context only works on run by the Cmd methods, not the embedded exec.Cmd.
 func CmdWithCtx(ctx context.Context, bin string, args ...string) *Cmd {
-	return WrapGoCmd(exec.CommandContext(ctx, bin, args...))
+	return NewCmd(bin, args...).WithContext(ctx)
 }
 
 // WrapGoCmd instance
@@ -402,7 +430,13 @@ func (c *Cmd) Output() (string, error) {
 		return "DRY-RUN: ok", nil
 	}
 
-	bs, err := c.Cmd.Output()
+	var bs []byte
+	var err error
+	if c.needCustomRun() {
+		bs, err = c.outputWithRedirects(false)
+	} else {
+		bs, err = c.Cmd.Output()
+	}
 
 	if c.AfterRun != nil {
 		c.AfterRun(c, err)
@@ -425,7 +459,14 @@ func (c *Cmd) CombinedOutput() (string, error) {
 		return "DRY-RUN: ok", nil
 	}
 
-	bs, err := c.Cmd.CombinedOutput()
+	var bs []byte
+	var err error
+	if c.needCustomRun() {
+		bs, err = c.outputWithRedirects(true)
+	} else {
+		bs, err = c.Cmd.CombinedOutput()
+	}
+
 	if c.AfterRun != nil {
 		c.AfterRun(c, err)
 	}
@@ -455,7 +496,7 @@ func (c *Cmd) Run() error {
 	}
 
 	// do running
-	err := c.Cmd.Run()
+	err := c.runWithRedirects()
 	if c.AfterRun != nil {
 		c.AfterRun(c, err)
 	}
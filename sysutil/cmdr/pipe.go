@@ -0,0 +1,227 @@
+package cmdr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// PipeError the error of a command in the pipeline.
+type PipeError struct {
+	// Index of the command in pipeline. start from 0
+	Index int
+	// Cmdline of the command
+	Cmdline string
+	// Err the run error of the command
+	Err error
+}
+
+// Error string
+func (e *PipeError) Error() string {
+	return fmt.Sprintf("pipe command#%d %q error: %v", e.Index+1, e.Cmdline, e.Err)
+}
+
+// Unwrap the run error
+func (e *PipeError) Unwrap() error { return e.Err }
+
+// Pipeline run multi commands and connect stdout to next stdin, like shell: `a | b | c`.
+//
+// Not depends on shell, so no quoting and platform differences problems.
+type Pipeline struct {
+	cmds []*Cmd
+	// Stdin for the first command
+	Stdin io.Reader
+	// Stdout for the last command
+	Stdout io.Writer
+	// Stderr for all commands that not set stderr
+	Stderr io.Writer
+	// DryRun setting. if True, not really execute commands
+	DryRun bool
+}
+
+// Pipe create a pipeline with commands. like shell: `a | b | c`
+//
+// Usage:
+//
+//	out, err := cmdr.Pipe(
+//		cmdr.NewCmd("cat", "go.mod"),
+//		cmdr.NewCmd("grep", "require").Tee(os.Stderr),
+//		cmdr.NewCmd("wc", "-l"),
+//	).Output()
+func Pipe(cmds ...*Cmd) *Pipeline {
+	return &Pipeline{cmds: cmds}
+}
+
+// Pipe add more commands to the pipeline
+func (p *Pipeline) Pipe(cmds ...*Cmd) *Pipeline {
+	p.cmds = append(p.cmds, cmds...)
+	return p
+}
+
+// WithStdin set stdin for the first command
+func (p *Pipeline) WithStdin(in io.Reader) *Pipeline {
+	p.Stdin = in
+	return p
+}
+
+// WithOutput set stdout for the last command, and stderr for all commands
+func (p *Pipeline) WithOutput(out, errOut io.Writer) *Pipeline {
+	p.Stdout = out
+	if errOut != nil {
+		p.Stderr = errOut
+	}
+	return p
+}
+
+// WithDryRun on run pipeline
+func (p *Pipeline) WithDryRun(dryRun bool) *Pipeline {
+	p.DryRun = dryRun
+	return p
+}
+
+// Cmds get all commands
+func (p *Pipeline) Cmds() []*Cmd { return p.cmds }
+
+// Cmdline of the pipeline. eg: "cat go.mod | grep require"
+func (p *Pipeline) Cmdline() string {
+	ss := make([]string, len(p.cmds))
+	for i, c := range p.cmds {
+		ss[i] = c.RawLine()
+	}
+	return strings.Join(ss, " | ")
+}
+
+// Output run and return the output of the last command
+func (p *Pipeline) Output() (string, error) {
+	if p.Stdout != nil {
+		return "", errStdoutSet
+	}
+	if p.DryRun {
+		return "DRY-RUN: ok", p.Run()
+	}
+
+	buf := new(bytes.Buffer)
+	p.Stdout = buf
+	err := p.Run()
+	return buf.String(), err
+}
+
+// Run all commands in pipeline and wait them exit.
+//
+// The error is like the shell `set -o pipefail`:
+// returns the error of the rightmost command that failed. see PipeError
+func (p *Pipeline) Run() error {
+	if len(p.cmds) == 0 {
+		return errors.New("cmdr: pipeline has no command")
+	}
+
+	for _, c := range p.cmds {
+		if c.BeforeRun != nil {
+			c.BeforeRun(c)
+		}
+	}
+	if p.DryRun {
+		return nil
+	}
+
+	ln := len(p.cmds)
+	// the read and write ends of the pipes. prs[i] is stdin of cmd i, pws[i] is stdout of cmd i.
+	prs := make([]*os.File, ln)
+	pws := make([]*os.File, ln)
+	closeFns := make([]func(), 0, ln)
+	defer func() {
+		for _, fn := range closeFns {
+			fn()
+		}
+	}()
+
+	// connect the commands
+	for i, c := range p.cmds {
+		if i == 0 {
+			if c.Stdin == nil {
+				c.Stdin = p.Stdin
+			}
+		} else {
+			c.Stdin = prs[i]
+		}
+
+		if c.Stderr == nil {
+			c.Stderr = p.Stderr
+		}
+
+		if i < ln-1 {
+			pr, pw, err := os.Pipe()
+			if err != nil {
+				closeFiles(prs...)
+				closeFiles(pws...)
+				return err
+			}
+			c.Stdout = pw
+			pws[i], prs[i+1] = pw, pr
+		} else if c.Stdout == nil {
+			c.Stdout = p.Stdout
+		}
+
+		closeFn, err := c.applyRedirects()
+		if err != nil {
+			closeFiles(prs...)
+			closeFiles(pws...)
+			return &PipeError{Index: i, Cmdline: c.RawLine(), Err: err}
+		}
+		closeFns = append(closeFns, closeFn)
+	}
+
+	// start the commands
+	for i, c := range p.cmds {
+		err := c.startProc()
+		// the read end has been used by the command, close it in current process.
+		closeFiles(prs[i])
+
+		if err != nil {
+			for j := 0; j < i; j++ {
+				_ = killProcess(p.cmds[j].Process, p.cmds[j].NewGroup)
+				_ = p.cmds[j].waitProc()
+			}
+			closeFiles(prs[i+1:]...)
+			closeFiles(pws...)
+			return &PipeError{Index: i, Cmdline: c.RawLine(), Err: err}
+		}
+	}
+
+	// wait all commands exit
+	errs := make([]error, ln)
+	var wg sync.WaitGroup
+	for i, c := range p.cmds {
+		wg.Add(1)
+		go func(i int, c *Cmd) {
+			defer wg.Done()
+			errs[i] = c.waitProc()
+			// close write end, the next command will read EOF
+			closeFiles(pws[i])
+		}(i, c)
+	}
+	wg.Wait()
+
+	var err error
+	for i, c := range p.cmds {
+		if c.AfterRun != nil {
+			c.AfterRun(c, errs[i])
+		}
+		if errs[i] != nil {
+			err = &PipeError{Index: i, Cmdline: c.RawLine(), Err: errs[i]}
+		}
+	}
+	return err
+}
+
+func closeFiles(fs ...*os.File) {
+	for _, f := range fs {
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+}
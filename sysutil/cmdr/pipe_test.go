@@ -0,0 +1,127 @@
+package cmdr_test
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/sysutil/cmdr"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestPipe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	p := cmdr.Pipe(
+		cmdr.NewCmd("printf", `c\na\nb\na\n`),
+		cmdr.NewCmd("sort"),
+		cmdr.NewCmd("uniq"),
+	)
+	assert.Eq(t, `printf c\na\nb\na\n | sort | uniq`, p.Cmdline())
+	assert.Len(t, p.Cmds(), 3)
+
+	out, err := p.Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "a\nb\nc\n", out)
+
+	// with stdin and tee
+	tee := new(bytes.Buffer)
+	out, err = cmdr.Pipe(cmdr.NewCmd("sort").Tee(tee)).
+		Pipe(cmdr.NewCmd("head", "-n", "1")).
+		WithStdin(strings.NewReader("b\na\n")).
+		Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "a\n", out)
+	assert.Eq(t, "a\nb\n", tee.String())
+
+	// dry run
+	out, err = cmdr.Pipe(cmdr.NewCmd("not-exist-bin")).WithDryRun(true).Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "DRY-RUN: ok", out)
+
+	_, err = cmdr.Pipe().Output()
+	assert.Err(t, err)
+}
+
+func TestPipe_error(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	// pipefail: the first command failed
+	out, err := cmdr.Pipe(cmdr.NewCmd("sh", "-c", "echo hi; exit 3"), cmdr.NewCmd("cat")).Output()
+	assert.Eq(t, "hi\n", out)
+	var pe *cmdr.PipeError
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, 0, pe.Index)
+	var ee *exec.ExitError
+	assert.True(t, errors.As(err, &ee))
+	assert.Eq(t, 3, ee.ExitCode())
+
+	// rightmost failed command
+	_, err = cmdr.Pipe(
+		cmdr.NewCmd("sh", "-c", "exit 1"),
+		cmdr.NewCmd("sh", "-c", "cat; exit 2"),
+		cmdr.NewCmd("cat"),
+	).Output()
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, 1, pe.Index)
+	assert.StrContains(t, err.Error(), "pipe command#2")
+
+	// start failed
+	_, err = cmdr.Pipe(cmdr.NewCmd("echo", "hi"), cmdr.WrapGoCmd(&exec.Cmd{Path: "/not-exist-bin"})).Output()
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, 1, pe.Index)
+}
+
+func TestCmd_redirects(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "out.log")
+
+	// > file 2>&1
+	err := cmdr.NewCmd("sh", "-c", "echo out; echo err >&2").StdoutToFile(outFile).StderrToStdout().Run()
+	assert.NoErr(t, err)
+	assert.Eq(t, "out\nerr\n", fsutil.ReadString(outFile))
+
+	// >> file, 2> file
+	errFile := filepath.Join(dir, "err.log")
+	err = cmdr.NewCmd("sh", "-c", "echo out2; echo err2 >&2").AppendToFile(outFile).StderrToFile(errFile).Run()
+	assert.NoErr(t, err)
+	assert.Eq(t, "out\nerr\nout2\n", fsutil.ReadString(outFile))
+	assert.Eq(t, "err2\n", fsutil.ReadString(errFile))
+
+	// < file, tee
+	tee := new(bytes.Buffer)
+	out, err := cmdr.NewCmd("cat").StdinFromFile(errFile).Tee(tee).Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "err2\n", out)
+	assert.Eq(t, "err2\n", tee.String())
+
+	// 2>&1 in pipeline
+	out, err = cmdr.Pipe(cmdr.NewCmd("sh", "-c", "echo err3 >&2").StderrToStdout(), cmdr.NewCmd("cat")).Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "err3\n", out)
+
+	// combined output
+	out, err = cmdr.NewCmd("sh", "-c", "echo out; echo err >&2").Tee(new(bytes.Buffer)).CombinedOutput()
+	assert.NoErr(t, err)
+	assert.StrContains(t, out, "err\n")
+
+	// redirect error
+	err = cmdr.NewCmd("cat").StdinFromFile(dir + "/not-exist").Run()
+	assert.Err(t, err)
+}
@@ -0,0 +1,292 @@
+package cmdr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// some default settings for run command
+var (
+	// DefaultGracePeriod wait for the process exit after send SIGTERM, then send SIGKILL.
+	DefaultGracePeriod = 5 * time.Second
+	// DefaultMaxOutput max bytes to capture stdout and stderr for Cmd.Execute()
+	DefaultMaxOutput = 1024 * 1024
+)
+
+// ErrTimeout error on run command timeout
+var ErrTimeout = errors.New("cmdr: command timed out")
+
+// Result of the command execution. see Cmd.Execute()
+type Result struct {
+	// ExitCode of the process. -1 if the process not started or killed by signal.
+	ExitCode int
+	// Signal that killed the process. nil if exited normally.
+	//
+	// NOTE: always nil on Windows
+	Signal os.Signal
+	// Duration of the command running
+	Duration time.Duration
+	// Stdout and Stderr captured output, only keep the last Cmd.MaxOutput bytes.
+	Stdout, Stderr string
+	// Truncated mark the captured output is truncated.
+	Truncated bool
+	// TimedOut mark the command is killed by timeout.
+	TimedOut bool
+	// Err of run the command
+	Err error
+}
+
+// Success check the command is exited with code 0
+func (r *Result) Success() bool { return r.Err == nil && r.ExitCode == 0 }
+
+// WithContext set context for run command.
+// when the context done, will terminate the process like on timeout.
+func (c *Cmd) WithContext(ctx context.Context) *Cmd {
+	c.ctx = ctx
+	return c
+}
+
+// WithTimeout set timeout for run command, and can with grace period.
+//
+// On timeout, will send SIGTERM to the process first, then send SIGKILL after the grace period.
+func (c *Cmd) WithTimeout(timeout time.Duration, gracePeriod ...time.Duration) *Cmd {
+	c.Timeout = timeout
+	if len(gracePeriod) > 0 {
+		c.GracePeriod = gracePeriod[0]
+	}
+	return c
+}
+
+// WithProcessGroup run the command in a new process group,
+// so the whole process tree will be killed on timeout or context done.
+func (c *Cmd) WithProcessGroup() *Cmd {
+	c.NewGroup = true
+	return c
+}
+
+// Execute run the command and returns the result with exit code, signal, duration
+// and captured stdout, stderr(size-capped by MaxOutput).
+//
+// The captured output is also written to the Stdout, Stderr if has been set.
+//
+// Usage:
+//
+//	res, err := cmdr.NewCmd("git", "fetch").WithTimeout(time.Minute).WithProcessGroup().Execute()
+//	if err != nil {
+//		fmt.Println(res.ExitCode, res.TimedOut, res.Stderr)
+//	}
+func (c *Cmd) Execute() (*Result, error) {
+	res := &Result{ExitCode: -1}
+	if c.BeforeRun != nil {
+		c.BeforeRun(c)
+	}
+
+	if c.DryRun {
+		res.ExitCode = 0
+		return res, nil
+	}
+
+	closeFn, err := c.applyRedirects()
+	if err != nil {
+		res.Err = err
+		return res, err
+	}
+	defer closeFn()
+
+	maxSize := c.MaxOutput
+	if maxSize <= 0 {
+		maxSize = DefaultMaxOutput
+	}
+
+	outBuf, errBuf := &tailBuffer{max: maxSize}, &tailBuffer{max: maxSize}
+	sameOut := c.Stderr != nil && sameWriter(c.Stderr, c.Stdout)
+
+	c.Stdout = teeWriter(c.Stdout, outBuf)
+	if sameOut {
+		c.Stderr = c.Stdout // 2>&1, keep same writer
+	} else {
+		c.Stderr = teeWriter(c.Stderr, errBuf)
+	}
+
+	startAt := time.Now()
+	err = c.startProc()
+	if err == nil {
+		err = c.waitProc()
+	}
+	res.Duration = time.Since(startAt)
+
+	if c.AfterRun != nil {
+		c.AfterRun(c, err)
+	}
+
+	res.Err = err
+	res.Stdout, res.Stderr = outBuf.String(), errBuf.String()
+	res.Truncated = outBuf.truncated || errBuf.truncated
+	res.TimedOut = c.watch != nil && c.watch.timedOut
+	if ps := c.ProcessState; ps != nil {
+		res.ExitCode = ps.ExitCode()
+		res.Signal = exitSignal(ps)
+	}
+	return res, err
+}
+
+// check need custom run for the command. eg: timeout, context, redirects
+func (c *Cmd) needCustomRun() bool {
+	return len(c.redirects) > 0 || c.Timeout > 0 || c.ctx != nil || c.NewGroup || c.PTY
+}
+
+// killWatcher terminate the process on timeout or context done.
+type killWatcher struct {
+	stopCh chan struct{}
+	doneCh chan struct{}
+	// mark the process is killed by timeout. read after doneCh closed
+	timedOut bool
+}
+
+// start the process, and start the kill watcher on timeout or context done.
+func (c *Cmd) startProc() error {
+	if c.ctx != nil {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	if c.PTY {
+		// the PTY process is started in a new session, it is also a new process group.
+		if err := c.startPTY(); err != nil {
+			return err
+		}
+	} else {
+		if c.NewGroup {
+			setProcessGroup(c.Cmd)
+		}
+		if err := c.Cmd.Start(); err != nil {
+			return err
+		}
+	}
+
+	c.watch = nil
+	if c.Timeout > 0 || c.ctx != nil {
+		c.watch = &killWatcher{stopCh: make(chan struct{}), doneCh: make(chan struct{})}
+		go c.watchKill(c.watch)
+	}
+	return nil
+}
+
+// wait the process exit, and stop the kill watcher.
+func (c *Cmd) waitProc() error {
+	err := c.Cmd.Wait()
+	if c.pty != nil {
+		c.pty.finish(c)
+	}
+	if c.watch == nil {
+		return err
+	}
+
+	close(c.watch.stopCh)
+	<-c.watch.doneCh
+
+	if err != nil {
+		if c.watch.timedOut {
+			return fmt.Errorf("%w after %s: %v", ErrTimeout, c.Timeout, err)
+		}
+		if c.ctx != nil && c.ctx.Err() != nil {
+			return c.ctx.Err()
+		}
+	}
+	return err
+}
+
+func (c *Cmd) watchKill(kw *killWatcher) {
+	defer close(kw.doneCh)
+
+	var timeoutCh <-chan time.Time
+	if c.Timeout > 0 {
+		timer := time.NewTimer(c.Timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	var ctxDone <-chan struct{}
+	if c.ctx != nil {
+		ctxDone = c.ctx.Done()
+	}
+
+	select {
+	case <-kw.stopCh:
+		return
+	case <-timeoutCh:
+		kw.timedOut = true
+	case <-ctxDone:
+	}
+
+	grace := c.GracePeriod
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+
+	// send SIGTERM, if it fails(eg: on Windows), kill directly.
+	if err := terminateProcess(c.Process, c.NewGroup); err != nil {
+		_ = killProcess(c.Process, c.NewGroup)
+		return
+	}
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-kw.stopCh:
+		// make sure the sub processes in the group are killed
+		if c.NewGroup {
+			_ = killProcess(c.Process, true)
+		}
+	case <-timer.C:
+		_ = killProcess(c.Process, c.NewGroup)
+	}
+}
+
+// sameWriter check the two writers are same. the non-comparable writers will not panic.
+func sameWriter(w1, w2 io.Writer) bool {
+	typ := reflect.TypeOf(w1)
+	return typ == reflect.TypeOf(w2) && typ.Comparable() && w1 == w2
+}
+
+func teeWriter(w io.Writer, buf *tailBuffer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(w, buf)
+}
+
+// tailBuffer only keep the last max bytes.
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+	// mark has dropped data
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,22 @@
+//go:build !unix && !windows
+
+package cmdr
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup on the OS is not supported. eg: plan9, js/wasm
+func setProcessGroup(_ *exec.Cmd) {}
+
+func terminateProcess(_ *os.Process, _ bool) error {
+	return errors.New("terminate process is not supported on the OS")
+}
+
+func killProcess(p *os.Process, _ bool) error {
+	return p.Kill()
+}
+
+func exitSignal(_ *os.ProcessState) os.Signal { return nil }
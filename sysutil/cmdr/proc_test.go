@@ -0,0 +1,137 @@
+package cmdr_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/sysutil/cmdr"
+	"github.com/gookit/goutil/x/assert"
+)
+
+type writerFunc func(p []byte) (int, error)
+
+func (fn writerFunc) Write(p []byte) (int, error) { return fn(p) }
+
+func TestCmd_Execute(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	res, err := cmdr.NewCmd("sh", "-c", "echo out; echo err >&2; exit 3").Execute()
+	assert.Err(t, err)
+	assert.False(t, res.Success())
+	assert.Eq(t, 3, res.ExitCode)
+	assert.Nil(t, res.Signal)
+	assert.Eq(t, "out\n", res.Stdout)
+	assert.Eq(t, "err\n", res.Stderr)
+	assert.False(t, res.TimedOut)
+	assert.True(t, res.Duration > 0)
+
+	// size capped output
+	c := cmdr.NewCmd("sh", "-c", "echo 0123456789")
+	c.MaxOutput = 4
+	res, err = c.Execute()
+	assert.NoErr(t, err)
+	assert.True(t, res.Success())
+	assert.Eq(t, "789\n", res.Stdout)
+	assert.True(t, res.Truncated)
+
+	// the non-comparable writers should not panic
+	var outs []string
+	fw := writerFunc(func(p []byte) (int, error) {
+		outs = append(outs, string(p))
+		return len(p), nil
+	})
+	c = cmdr.NewCmd("sh", "-c", "echo out")
+	c.Stdout, c.Stderr = fw, fw
+	res, err = c.Execute()
+	assert.NoErr(t, err)
+	assert.Eq(t, "out\n", res.Stdout)
+	assert.Eq(t, []string{"out\n"}, outs)
+
+	// not found
+	res, err = cmdr.NewCmd("not-exist-bin").Execute()
+	assert.Err(t, err)
+	assert.Eq(t, -1, res.ExitCode)
+
+	// dry run
+	res, err = cmdr.NewCmd("not-exist-bin").WithDryRun(true).Execute()
+	assert.NoErr(t, err)
+	assert.True(t, res.Success())
+}
+
+func TestCmd_WithTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	// SIGTERM
+	start := time.Now()
+	res, err := cmdr.NewCmd("sleep", "5").WithTimeout(50 * time.Millisecond).Execute()
+	assert.True(t, errors.Is(err, cmdr.ErrTimeout))
+	assert.True(t, res.TimedOut)
+	assert.Eq(t, -1, res.ExitCode)
+	assert.Eq(t, syscall.SIGTERM, res.Signal)
+	assert.True(t, time.Since(start) < 3*time.Second)
+
+	// ignore SIGTERM, escalate to SIGKILL
+	res, err = cmdr.NewCmd("sh", "-c", "trap '' TERM; echo ready; sleep 5").
+		WithTimeout(100*time.Millisecond, 100*time.Millisecond).
+		WithProcessGroup().
+		Execute()
+	assert.Err(t, err)
+	assert.Eq(t, syscall.SIGKILL, res.Signal)
+	assert.Eq(t, "ready\n", res.Stdout)
+
+	// kill the process group, the sub process will not hold the stdout pipe.
+	start = time.Now()
+	out, err := cmdr.NewCmd("sh", "-c", "sleep 5; echo done").
+		WithTimeout(50 * time.Millisecond).
+		WithProcessGroup().
+		Output()
+	assert.Err(t, err)
+	assert.Empty(t, out)
+	assert.True(t, time.Since(start) < 3*time.Second)
+
+	// not timeout
+	out, err = cmdr.NewCmd("echo", "ok").WithTimeout(time.Second).Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "ok\n", out)
+}
+
+func TestCmdWithCtx(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := cmdr.CmdWithCtx(ctx, "sleep", "5").Run()
+	assert.Eq(t, context.DeadlineExceeded, err)
+
+	// context has been done
+	err = cmdr.CmdWithCtx(ctx, "echo", "ok").Run()
+	assert.Eq(t, context.DeadlineExceeded, err)
+
+	out, err := cmdr.CmdWithCtx(context.Background(), "echo", "ok").Output()
+	assert.NoErr(t, err)
+	assert.True(t, strings.HasPrefix(out, "ok"))
+
+	// terminate by SIGTERM on context done
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel2()
+
+	res, err := cmdr.CmdWithCtx(ctx2, "sleep", "2").Execute()
+	assert.Eq(t, context.DeadlineExceeded, err)
+	assert.Eq(t, syscall.SIGTERM, res.Signal)
+	assert.True(t, res.Duration < time.Second)
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+package cmdr
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(c *exec.Cmd) {
+	if c.SysProcAttr == nil {
+		c.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	c.SysProcAttr.Setpgid = true
+}
+
+func signalProcess(p *os.Process, group bool, sig syscall.Signal) error {
+	if group {
+		return syscall.Kill(-p.Pid, sig)
+	}
+	return p.Signal(sig)
+}
+
+func terminateProcess(p *os.Process, group bool) error {
+	return signalProcess(p, group, syscall.SIGTERM)
+}
+
+func killProcess(p *os.Process, group bool) error {
+	return signalProcess(p, group, syscall.SIGKILL)
+}
+
+func exitSignal(ps *os.ProcessState) os.Signal {
+	if ws, ok := ps.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return ws.Signal()
+	}
+	return nil
+}
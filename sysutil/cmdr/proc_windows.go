@@ -0,0 +1,34 @@
+package cmdr
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+func setProcessGroup(c *exec.Cmd) {
+	if c.SysProcAttr == nil {
+		c.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	c.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// windows not support SIGTERM, returns error for kill directly.
+func terminateProcess(_ *os.Process, _ bool) error {
+	return errors.New("terminate process is not supported on windows")
+}
+
+func killProcess(p *os.Process, group bool) error {
+	if group {
+		// kill the process tree
+		err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(p.Pid)).Run()
+		if err == nil {
+			return nil
+		}
+	}
+	return p.Kill()
+}
+
+func exitSignal(_ *os.ProcessState) os.Signal { return nil }
@@ -0,0 +1,171 @@
+package cmdr
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// PTYDrainTimeout max wait time for read the remaining PTY output after the process exited.
+//
+// NOTE: the background sub processes may keep the PTY open, so need a timeout.
+var PTYDrainTimeout = time.Second
+
+// WithPTY run the command in a pseudo-terminal(PTY), so the command will see a TTY
+// on stdin, stdout and stderr. useful for interactive tools, eg: ssh, git with prompts, editors.
+//
+//   - if the Stdin is a terminal, it will be set to raw mode on running.
+//   - the terminal size changes will be forwarded to the PTY.
+//   - the stdout and stderr are merged and written to Cmd.Stdout, the line ending is "\r\n".
+//
+// NOTE: only supported on Linux, run will return error on other OS.
+//
+// Usage:
+//
+//	err := cmdr.NewCmd("ssh", "user@host").WithPTY().ToOSStdoutStderr().WithStdin(os.Stdin).Run()
+func (c *Cmd) WithPTY() *Cmd {
+	c.PTY = true
+	return c
+}
+
+// WithTranscript record the PTY session output to w. will enable the PTY mode.
+func (c *Cmd) WithTranscript(w io.Writer) *Cmd {
+	c.PTY = true
+	c.Transcript = w
+	return c
+}
+
+// ptySession of the command running in PTY
+type ptySession struct {
+	master *os.File
+	// the origin stdin, stdout, stderr of the command.
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// closed on read PTY output end.
+	outDone chan struct{}
+	// restore the terminal state and stop forward resize
+	restores []func()
+}
+
+// start the command with PTY as stdin, stdout and stderr.
+func (c *Cmd) startPTY() error {
+	master, slave, err := openPTY()
+	if err != nil {
+		return err
+	}
+
+	ps := &ptySession{
+		master:  master,
+		stdin:   c.Stdin,
+		stdout:  c.Stdout,
+		stderr:  c.Stderr,
+		outDone: make(chan struct{}),
+	}
+
+	c.Stdin, c.Stdout, c.Stderr = slave, slave, slave
+	setCtty(c.Cmd)
+	ps.syncTerm()
+
+	err = c.Cmd.Start()
+	// the slave has been used by the process, close it in current process.
+	_ = slave.Close()
+	if err != nil {
+		ps.restore(c)
+		return err
+	}
+
+	c.pty = ps
+	ps.forward(c.Transcript)
+	return nil
+}
+
+// sync the terminal size to PTY, and set the stdin to raw mode.
+func (ps *ptySession) syncTerm() {
+	var tty *os.File
+	if f, ok := ps.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		tty = f
+		if state, err := term.MakeRaw(int(f.Fd())); err == nil {
+			ps.restores = append(ps.restores, func() {
+				_ = term.Restore(int(f.Fd()), state)
+			})
+		}
+	} else if f, ok := ps.stdout.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		tty = f
+	}
+
+	if tty == nil {
+		return
+	}
+
+	_ = syncWinsize(tty, ps.master)
+	if stop := watchResize(func() { _ = syncWinsize(tty, ps.master) }); stop != nil {
+		ps.restores = append(ps.restores, stop)
+	}
+}
+
+// forward the stdin to PTY, and the PTY output to stdout and transcript.
+func (ps *ptySession) forward(transcript io.Writer) {
+	out := ps.stdout
+	if out == nil {
+		out = io.Discard
+	}
+	if transcript != nil {
+		out = io.MultiWriter(out, transcript)
+	}
+
+	go func() {
+		// on Linux, will return EIO after all slave fds closed, ignore it.
+		_, _ = io.Copy(out, ps.master)
+		close(ps.outDone)
+	}()
+
+	if ps.stdin == nil {
+		return
+	}
+
+	onEOF := func() {
+		// not a terminal input, send EOF(Ctrl+D) to the process
+		if f, ok := ps.stdin.(*os.File); !ok || !term.IsTerminal(int(f.Fd())) {
+			_, _ = ps.master.Write([]byte{4})
+		}
+	}
+
+	// the file(eg: os.Stdin) read can be stopped on finish, so it will not steal the input after exited.
+	if f, ok := ps.stdin.(*os.File); ok {
+		if stop, err := copyInput(ps.master, f, onEOF); err == nil {
+			ps.restores = append(ps.restores, stop)
+			return
+		}
+	}
+
+	go func() {
+		_, _ = io.Copy(ps.master, ps.stdin)
+		onEOF()
+	}()
+}
+
+// finish the session after the process exited.
+func (ps *ptySession) finish(c *Cmd) {
+	timer := time.NewTimer(PTYDrainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ps.outDone:
+	case <-timer.C:
+	}
+
+	ps.restore(c)
+	c.pty = nil
+}
+
+// close the PTY and restore the command stdin, stdout, stderr
+func (ps *ptySession) restore(c *Cmd) {
+	for _, fn := range ps.restores {
+		fn()
+	}
+	_ = ps.master.Close()
+	c.Stdin, c.Stdout, c.Stderr = ps.stdin, ps.stdout, ps.stderr
+}
@@ -0,0 +1,163 @@
+package cmdr
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// open a new PTY pair by /dev/ptmx
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var num int
+	err = controlFd(master, func(fd int) error {
+		// unlock the slave, and get the slave number
+		if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+			return err
+		}
+
+		var err error
+		num, err = unix.IoctlGetInt(fd, unix.TIOCGPTN)
+		return err
+	})
+
+	if err == nil {
+		slave, err = os.OpenFile("/dev/pts/"+strconv.Itoa(num), os.O_RDWR|syscall.O_NOCTTY, 0)
+	}
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// start the process in a new session, and use the PTY(stdin) as controlling terminal.
+func setCtty(c *exec.Cmd) {
+	if c.SysProcAttr == nil {
+		c.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	c.SysProcAttr.Setsid = true
+	c.SysProcAttr.Setctty = true
+	c.SysProcAttr.Ctty = 0
+}
+
+// copy the terminal size from tty to the PTY master
+func syncWinsize(tty, master *os.File) error {
+	ws, err := unix.IoctlGetWinsize(int(tty.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return err
+	}
+
+	return controlFd(master, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, ws)
+	})
+}
+
+// call fn on the terminal size changed(SIGWINCH), returns func for stop watch.
+func watchResize(fn func()) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+
+	go func() {
+		for range ch {
+			fn()
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(ch)
+	}
+}
+
+// copy the input file to dst by poll, until EOF or call the returned stop func.
+//
+// The blocked read on the file(eg: os.Stdin) cannot be interrupted, so use poll with a pipe for stop it.
+func copyInput(dst io.Writer, in *os.File, onEOF func()) (stop func(), err error) {
+	var fd int
+	if err = controlFd(in, func(v int) error {
+		fd = v
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		defer pr.Close()
+
+		fds := []unix.PollFd{
+			{Fd: int32(fd), Events: unix.POLLIN},
+			{Fd: int32(pr.Fd()), Events: unix.POLLIN},
+		}
+		buf := make([]byte, 32*1024)
+		for {
+			if _, err := unix.Poll(fds, -1); err != nil {
+				if err == unix.EINTR {
+					continue
+				}
+				return
+			}
+			// stopped
+			if fds[1].Revents != 0 {
+				return
+			}
+			if fds[0].Revents == 0 {
+				continue
+			}
+
+			n, err := unix.Read(fd, buf)
+			if err == unix.EINTR || err == unix.EAGAIN {
+				continue
+			}
+			if n > 0 {
+				if _, err = dst.Write(buf[:n]); err != nil {
+					return
+				}
+				continue
+			}
+
+			// n == 0 is EOF
+			if err == nil {
+				onEOF()
+			}
+			return
+		}
+	}()
+
+	return func() {
+		_ = pw.Close()
+		<-doneCh
+	}, nil
+}
+
+// control the raw fd. dont use f.Fd(), it will set the fd to blocking mode,
+// then the blocked read cannot be interrupted by close.
+func controlFd(f *os.File, fn func(fd int) error) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var fnErr error
+	if err = rc.Control(func(fd uintptr) { fnErr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return fnErr
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+
+package cmdr
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+)
+
+var errPTYNotSupported = errors.New("cmdr: PTY mode is only supported on Linux")
+
+func openPTY() (master, slave *os.File, err error) {
+	return nil, nil, errPTYNotSupported
+}
+
+func setCtty(_ *exec.Cmd) {}
+
+func syncWinsize(_, _ *os.File) error { return errPTYNotSupported }
+
+func watchResize(_ func()) (stop func()) { return nil }
+
+func copyInput(_ io.Writer, _ *os.File, _ func()) (stop func(), err error) {
+	return nil, errPTYNotSupported
+}
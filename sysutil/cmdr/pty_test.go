@@ -0,0 +1,87 @@
+package cmdr_test
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/sysutil/cmdr"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestCmd_WithPTY(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		_, err := cmdr.NewCmd("echo", "hi").WithPTY().Output()
+		assert.ErrSubMsg(t, err, "only supported on Linux")
+		return
+	}
+
+	out, err := cmdr.NewCmd("sh", "-c", "test -t 0 && test -t 1 && test -t 2 && echo is-tty").WithPTY().Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "is-tty\r\n", out)
+
+	// without PTY
+	out, _ = cmdr.NewCmd("sh", "-c", "test -t 1 && echo is-tty").Output()
+	assert.Empty(t, out)
+
+	// with stdin and transcript
+	transcript := new(bytes.Buffer)
+	c := cmdr.NewCmd("sh", "-c", "read name; echo hello $name >&2").
+		WithStdin(strings.NewReader("inhere\n")).
+		WithTranscript(transcript)
+	out, err = c.Output()
+	assert.NoErr(t, err)
+	assert.StrContains(t, out, "hello inhere\r\n")
+	assert.Eq(t, out, transcript.String())
+	// restored after run
+	_, ok := c.Stdout.(*bytes.Buffer)
+	assert.True(t, ok)
+
+	// exit code
+	res, err := cmdr.NewCmd("sh", "-c", "exit 3").WithPTY().Execute()
+	assert.Err(t, err)
+	assert.Eq(t, 3, res.ExitCode)
+
+	// with timeout
+	res, err = cmdr.NewCmd("sleep", "5").WithPTY().WithTimeout(100 * time.Millisecond).Execute()
+	assert.ErrIs(t, err, cmdr.ErrTimeout)
+	assert.True(t, res.TimedOut)
+	assert.True(t, res.Duration < 3*time.Second)
+}
+
+func TestCmd_WithPTY_releaseStdin(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("PTY only supported on Linux")
+		return
+	}
+
+	pr, pw, err := os.Pipe()
+	assert.NoErr(t, err)
+	defer pr.Close()
+	defer pw.Close()
+
+	out, err := cmdr.NewCmd("echo", "done").WithPTY().WithStdin(pr).Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "done\r\n", out)
+
+	// the stdin should not be read after the process exited
+	_, err = pw.Write([]byte("after\n"))
+	assert.NoErr(t, err)
+
+	readCh := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 16)
+		n, _ := pr.Read(buf)
+		readCh <- string(buf[:n])
+	}()
+
+	select {
+	case s := <-readCh:
+		assert.Eq(t, "after\n", s)
+	case <-time.After(time.Second):
+		t.Fatal("the stdin is not released after the process exited")
+	}
+}
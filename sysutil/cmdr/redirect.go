@@ -0,0 +1,157 @@
+package cmdr
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/gookit/goutil/fsutil"
+)
+
+var (
+	errStdoutSet = errors.New("cmdr: Stdout already set")
+	errStderrSet = errors.New("cmdr: Stderr already set")
+)
+
+// redirectFn will be applied before run command. returns the opened file for close after run.
+type redirectFn func(c *Cmd) (io.Closer, error)
+
+// Tee copy the stdout of the command to w, like shell: `cmd | tee file`.
+// will keep the origin stdout output.
+func (c *Cmd) Tee(w io.Writer) *Cmd {
+	return c.addRedirect(func(c *Cmd) (io.Closer, error) {
+		if c.Stdout == nil {
+			c.Stdout = w
+		} else {
+			c.Stdout = io.MultiWriter(c.Stdout, w)
+		}
+		return nil, nil
+	})
+}
+
+// StdoutToFile redirect stdout to the file, like shell: `cmd > file`
+func (c *Cmd) StdoutToFile(fPath string) *Cmd {
+	return c.addRedirect(func(c *Cmd) (io.Closer, error) {
+		f, err := fsutil.OpenTruncFile(fPath)
+		if err == nil {
+			c.Stdout = f
+		}
+		return f, err
+	})
+}
+
+// AppendToFile redirect stdout and append to the file, like shell: `cmd >> file`
+func (c *Cmd) AppendToFile(fPath string) *Cmd {
+	return c.addRedirect(func(c *Cmd) (io.Closer, error) {
+		f, err := fsutil.OpenAppendFile(fPath)
+		if err == nil {
+			c.Stdout = f
+		}
+		return f, err
+	})
+}
+
+// StderrToFile redirect stderr to the file, like shell: `cmd 2> file`
+func (c *Cmd) StderrToFile(fPath string) *Cmd {
+	return c.addRedirect(func(c *Cmd) (io.Closer, error) {
+		f, err := fsutil.OpenTruncFile(fPath)
+		if err == nil {
+			c.Stderr = f
+		}
+		return f, err
+	})
+}
+
+// StderrToStdout redirect stderr to stdout, like shell: `cmd 2>&1`
+//
+// NOTE: the order is important as in shell. eg:
+//
+//	c.StdoutToFile("out.log").StderrToStdout() // cmd > out.log 2>&1
+func (c *Cmd) StderrToStdout() *Cmd {
+	return c.addRedirect(func(c *Cmd) (io.Closer, error) {
+		c.Stderr = c.Stdout
+		return nil, nil
+	})
+}
+
+// StdinFromFile read stdin from the file, like shell: `cmd < file`
+func (c *Cmd) StdinFromFile(fPath string) *Cmd {
+	return c.addRedirect(func(c *Cmd) (io.Closer, error) {
+		f, err := os.Open(fPath)
+		if err == nil {
+			c.Stdin = f
+		}
+		return f, err
+	})
+}
+
+func (c *Cmd) addRedirect(fn redirectFn) *Cmd {
+	c.redirects = append(c.redirects, fn)
+	return c
+}
+
+// apply redirects in order, returns func for close opened files.
+func (c *Cmd) applyRedirects() (closeFn func(), err error) {
+	var closers []io.Closer
+	closeFn = func() {
+		for _, cl := range closers {
+			_ = cl.Close()
+		}
+	}
+
+	for _, fn := range c.redirects {
+		cl, err := fn(c)
+		if err != nil {
+			closeFn()
+			return nil, err
+		}
+		if cl != nil {
+			closers = append(closers, cl)
+		}
+	}
+	return closeFn, nil
+}
+
+// run command with apply redirects, timeout and context
+func (c *Cmd) runWithRedirects() error {
+	closeFn, err := c.applyRedirects()
+	if err != nil {
+		return err
+	}
+
+	if err = c.startProc(); err == nil {
+		err = c.waitProc()
+	}
+	closeFn()
+	return err
+}
+
+// capture stdout(and stderr on combined) with apply redirects
+func (c *Cmd) outputWithRedirects(combined bool) ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errStdoutSet
+	}
+	if combined && c.Stderr != nil {
+		return nil, errStderrSet
+	}
+
+	buf := new(bytes.Buffer)
+	c.Stdout = buf
+
+	closeFn, err := c.applyRedirects()
+	if err != nil {
+		return nil, err
+	}
+
+	// set after redirects, so stdout and stderr are the same writer. like exec.Cmd.CombinedOutput()
+	if combined && c.Stderr == nil {
+		c.Stderr = c.Stdout
+	}
+
+	if err = c.startProc(); err == nil {
+		err = c.waitProc()
+	}
+	closeFn()
+	return buf.Bytes(), err
+}
@@ -17,14 +17,26 @@ import (
 type Task struct {
 	err   error
 	index int
+	// prev task on run by dependency graph. is the last done dependency.
+	prev    *Task
+	skipped bool
 
 	// ID for task
 	ID  string
 	Cmd *Cmd
+	// Depends on other task IDs, will run after all dependencies are done.
+	Depends []string
+	// IgnoreErr continue run other tasks on the task failed.
+	IgnoreErr bool
+	// Condition check before run the task. return false to skip the task.
+	Condition func(t *Task) bool
 
 	// BeforeRun hook
 	BeforeRun func(t *Task)
-	PrevCond  func(prev *Task) bool
+	// PrevCond check the prev task to decide whether to run current task.
+	//
+	// On run by dependency graph, the prev is the last done dependency task.
+	PrevCond func(prev *Task) bool
 }
 
 // NewTask instance
@@ -48,19 +60,35 @@ func (t *Task) ensureID(idx int) {
 	t.ID = id
 }
 
-var rpl = textutil.NewVarReplacer("$").DisableFlatten()
-
-// RunWith command
+// RunWith command. will render the vars in args and workdir.
+//
+// vars: the "cmdVars" in ctx, and the Cmd.Vars(will override same name)
 func (t *Task) RunWith(ctx maputil.Data) error {
 	cmdVars := ctx.StringMap("cmdVars")
+	if len(t.Cmd.Vars) > 0 {
+		// merge to new map, the ctx vars are shared by all tasks.
+		vars := make(map[string]string, len(cmdVars)+len(t.Cmd.Vars))
+		for name, val := range cmdVars {
+			vars[name] = val
+		}
+		for name, val := range t.Cmd.Vars {
+			vars[name] = val
+		}
+		cmdVars = vars
+	}
 
 	if len(cmdVars) > 0 {
-		// rpl := strutil.NewReplacer(cmdVars)
+		// NOTE: create replacer on each run, it is not safe for concurrent use.
+		rpl := textutil.NewVarReplacer("$").DisableFlatten()
 		for i, val := range t.Cmd.Args {
 			if strings.ContainsRune(val, '$') {
 				t.Cmd.Args[i] = rpl.RenderSimple(val, cmdVars)
 			}
 		}
+
+		if strings.ContainsRune(t.Cmd.Dir, '$') {
+			t.Cmd.Dir = rpl.RenderSimple(t.Cmd.Dir, cmdVars)
+		}
 	}
 
 	return t.Run()
@@ -96,6 +124,17 @@ func (t *Task) IsSuccess() bool {
 	return t.err == nil
 }
 
+// IsSkipped check the task is skipped by hooks, PrevCond, or the dependency is skipped or failed.
+func (t *Task) IsSkipped() bool {
+	return t.skipped
+}
+
+// DependOn add dependency task IDs
+func (t *Task) DependOn(ids ...string) *Task {
+	t.Depends = append(t.Depends, ids...)
+	return t
+}
+
 // RunnerHookFn func
 type RunnerHookFn func(r *Runner, t *Task) bool
 
@@ -108,7 +147,12 @@ type Runner struct {
 	// Errs on run tasks, key is Task.ID
 	Errs errorx.ErrMap
 
-	// TODO Concurrent run
+	// Concurrency max number of tasks run in parallel. default is 1
+	//
+	// If Concurrency > 1 or any task has Depends, will run tasks by dependency graph.
+	Concurrency int
+	// NoPrefix dont prefix the output lines by task ID on parallel run.
+	NoPrefix bool
 
 	// Workdir common workdir
 	Workdir string
@@ -213,15 +257,35 @@ func (r *Runner) AddCmdline(line string) *Runner {
 	})
 }
 
-// Run all tasks
+// WithConcurrency set max number of tasks run in parallel
+func (r *Runner) WithConcurrency(n int) *Runner {
+	r.Concurrency = n
+	return r
+}
+
+// Run all tasks.
+//
+// If Concurrency > 1 or any task has Depends, will run tasks by dependency graph:
+// the independent tasks run in parallel, and the dependents run after all dependencies are done.
 func (r *Runner) Run() error {
+	if r.useGraph() {
+		return r.runGraph()
+	}
+
 	// do run tasks
 	for i, task := range r.tasks {
 		if r.BeforeRun != nil && !r.BeforeRun(r, task) {
+			task.skipped = true
 			continue
 		}
 
 		if r.prev != nil && task.PrevCond != nil && !task.PrevCond(r.prev) {
+			task.skipped = true
+			continue
+		}
+
+		if task.Condition != nil && !task.Condition(task) {
+			task.skipped = true
 			continue
 		}
 
@@ -249,18 +313,7 @@ func (r *Runner) StepRun() error {
 
 // RunTask command
 func (r *Runner) RunTask(task *Task) (goon bool) {
-	if len(r.EnvMap) > 0 {
-		task.Cmd.AppendEnv(r.EnvMap)
-	}
-
-	if r.OutToStd && !task.Cmd.HasStdout() {
-		task.Cmd.ToOSStdoutStderr()
-	}
-
-	// common workdir
-	if r.Workdir != "" && task.Cmd.Dir == "" {
-		task.Cmd.WithWorkDir(r.Workdir)
-	}
+	r.prepareTask(task)
 
 	// do running
 	if err := task.RunWith(r.Params); err != nil {
@@ -282,6 +335,22 @@ func (r *Runner) RunTask(task *Task) (goon bool) {
 	return true
 }
 
+// prepare the task command by runner settings
+func (r *Runner) prepareTask(task *Task) {
+	if len(r.EnvMap) > 0 {
+		task.Cmd.AppendEnv(r.EnvMap)
+	}
+
+	if r.OutToStd && !task.Cmd.HasStdout() {
+		task.Cmd.ToOSStdoutStderr()
+	}
+
+	// common workdir
+	if r.Workdir != "" && task.Cmd.Dir == "" {
+		task.Cmd.WithWorkDir(r.Workdir)
+	}
+}
+
 // Len of tasks
 func (r *Runner) Len() int {
 	return len(r.tasks)
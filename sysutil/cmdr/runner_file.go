@@ -0,0 +1,295 @@
+package cmdr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/strutil"
+)
+
+// RunnerConfig the task definitions for create Runner. see LoadRunner()
+type RunnerConfig struct {
+	// Workdir common workdir for all tasks
+	Workdir string `json:"workdir" yaml:"workdir"`
+	// Env will append to all task commands
+	Env map[string]string `json:"env" yaml:"env"`
+	// Vars for render task cmdline and workdir. eg: $name
+	Vars map[string]string `json:"vars" yaml:"vars"`
+	// Concurrency max number of tasks run in parallel
+	Concurrency int `json:"concurrency" yaml:"concurrency"`
+	// IgnoreErr continue on any task error
+	IgnoreErr bool `json:"ignore_err" yaml:"ignore_err"`
+	// Tasks definitions
+	Tasks []*TaskConfig `json:"tasks" yaml:"tasks"`
+}
+
+// TaskConfig the task definition in RunnerConfig
+type TaskConfig struct {
+	ID string `json:"id" yaml:"id"`
+	// Cmdline for run. eg: "go build -o $out ./cmd/app"
+	Cmdline string            `json:"cmdline" yaml:"cmdline"`
+	Workdir string            `json:"workdir" yaml:"workdir"`
+	Env     map[string]string `json:"env" yaml:"env"`
+	// Vars for the task, will override the same name in RunnerConfig.Vars
+	Vars    map[string]string `json:"vars" yaml:"vars"`
+	Depends []string          `json:"depends" yaml:"depends"`
+	// Condition for run the task. allow:
+	//
+	//	- "prev.success" run on the prev task is success
+	//	- "prev.failed" run on the prev task is failed
+	//	- other will as a command line, run the task on exit code is 0. eg: "test -f go.mod"
+	Condition string `json:"condition" yaml:"condition"`
+	IgnoreErr bool   `json:"ignore_err" yaml:"ignore_err"`
+}
+
+// DecodeFunc decode the file contents to RunnerConfig
+type DecodeFunc func(bs []byte, ptr any) error
+
+// RunnerDecoders for LoadRunner, key is file ext. can register more, eg: YAML
+//
+// Usage:
+//
+//	cmdr.RunnerDecoders[".yaml"] = yaml.Unmarshal
+//	cmdr.RunnerDecoders[".yml"] = yaml.Unmarshal
+var RunnerDecoders = map[string]DecodeFunc{
+	".json": json.Unmarshal,
+	".ini":  DecodeIniTasks,
+	".conf": DecodeIniTasks,
+}
+
+// LoadRunner create Runner from the task definitions file. the file format is detected by ext.
+// see RunnerDecoders for supported formats.
+//
+// Usage:
+//
+//	rr, err := cmdr.LoadRunner("tasks.json")
+//	if err != nil {
+//		return err
+//	}
+//	err = rr.Run()
+func LoadRunner(fPath string, fns ...func(rr *Runner)) (*Runner, error) {
+	ext := strings.ToLower(filepath.Ext(fPath))
+	decode, ok := RunnerDecoders[ext]
+	if !ok {
+		return nil, fmt.Errorf("cmdr: not supported tasks file format %q", ext)
+	}
+
+	bs, err := os.ReadFile(fPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &RunnerConfig{}
+	if err = decode(bs, cfg); err != nil {
+		return nil, fmt.Errorf("cmdr: decode tasks file %q error: %w", fPath, err)
+	}
+	return NewRunnerFromConfig(cfg, fns...)
+}
+
+// NewRunnerFromConfig create Runner from RunnerConfig
+func NewRunnerFromConfig(cfg *RunnerConfig, fns ...func(rr *Runner)) (*Runner, error) {
+	rr := NewRunner(func(rr *Runner) {
+		rr.Workdir = cfg.Workdir
+		rr.EnvMap = cfg.Env
+		rr.Concurrency = cfg.Concurrency
+		rr.IgnoreErr = cfg.IgnoreErr
+		if len(cfg.Vars) > 0 {
+			rr.Params["cmdVars"] = cfg.Vars
+		}
+	})
+
+	for i, tc := range cfg.Tasks {
+		if strings.TrimSpace(tc.Cmdline) == "" {
+			return nil, fmt.Errorf("cmdr: the task#%d cmdline cannot be empty", i+1)
+		}
+
+		task := &Task{
+			ID:        tc.ID,
+			Cmd:       NewCmdline(tc.Cmdline).WithWorkDir(tc.Workdir).AppendEnv(tc.Env).WithVars(tc.Vars),
+			Depends:   tc.Depends,
+			IgnoreErr: tc.IgnoreErr,
+		}
+		if err := rr.setTaskCond(task, tc.Condition); err != nil {
+			return nil, err
+		}
+
+		if _, err := rr.Task(task.ID); task.ID != "" && err == nil {
+			return nil, fmt.Errorf("cmdr: the task ID %q is repeated", task.ID)
+		}
+		rr.AddTask(task)
+	}
+
+	for _, fn := range fns {
+		fn(rr)
+	}
+	return rr, nil
+}
+
+func (r *Runner) setTaskCond(task *Task, cond string) error {
+	switch cond = strings.TrimSpace(cond); cond {
+	case "":
+	case "prev.success":
+		task.PrevCond = func(prev *Task) bool { return prev.IsSuccess() }
+	case "prev.failed":
+		task.PrevCond = func(prev *Task) bool { return !prev.IsSuccess() }
+	default:
+		if strings.HasPrefix(cond, "prev.") {
+			return fmt.Errorf("cmdr: invalid task condition %q", cond)
+		}
+
+		// NOTE: the condition is checked before prepareTask(), so apply the runner workdir and env too.
+		task.Condition = func(t *Task) bool {
+			c := NewCmdline(cond).WithVars(t.Cmd.Vars)
+			c.WithWorkDir(strutil.OrElse(t.Cmd.Dir, r.Workdir))
+			c.Env = t.Cmd.Env
+			c.AppendEnv(r.EnvMap)
+			return (&Task{Cmd: c}).RunWith(r.Params) == nil
+		}
+	}
+	return nil
+}
+
+// DecodeIniTasks decode INI-like text contents to RunnerConfig.
+//
+// Format eg:
+//
+//	# global settings
+//	workdir = ./
+//	concurrency = 2
+//
+//	[vars]
+//	out = bin/app
+//
+//	[env]
+//	CGO_ENABLED = 0
+//
+//	[task.build]
+//	cmdline = go build -o $out ./cmd/app
+//	depends = gen, lint
+//
+//	[task.build.env]
+//	GOOS = linux
+func DecodeIniTasks(bs []byte, ptr any) error {
+	cfg, ok := ptr.(*RunnerConfig)
+	if !ok {
+		return fmt.Errorf("cmdr: decode INI tasks only support *RunnerConfig, got %T", ptr)
+	}
+
+	var section string
+	var task *TaskConfig
+	// task ID => task config. the task sections can be in any order. eg: [task.a], [task.b], [task.a.env]
+	taskMap := make(map[string]*TaskConfig, len(cfg.Tasks))
+	for _, tc := range cfg.Tasks {
+		taskMap[tc.ID] = tc
+	}
+	s := bufio.NewScanner(bytes.NewReader(bs))
+
+	for num := 1; s.Scan(); num++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		// section. eg: [task.build]
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return fmt.Errorf("invalid section at line#%d: %s", num, line)
+			}
+
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if strings.HasPrefix(section, "task.") {
+				id, sub, _ := strings.Cut(section[5:], ".")
+				if task = taskMap[id]; task == nil {
+					task = &TaskConfig{ID: id}
+					taskMap[id] = task
+					cfg.Tasks = append(cfg.Tasks, task)
+				}
+				section = "task." + sub
+			}
+			continue
+		}
+
+		key, val := strutil.TrimCut(line, "=")
+		if key == "" || !strings.ContainsRune(line, '=') {
+			return fmt.Errorf("invalid key-value at line#%d: %s", num, line)
+		}
+		val = unquoteValue(val)
+
+		var err error
+		switch section {
+		case "":
+			err = setRunnerValue(cfg, key, val)
+		case "vars":
+			cfg.Vars = setMapValue(cfg.Vars, key, val)
+		case "env":
+			cfg.Env = setMapValue(cfg.Env, key, val)
+		case "task.":
+			err = setTaskValue(task, key, val)
+		case "task.vars":
+			task.Vars = setMapValue(task.Vars, key, val)
+		case "task.env":
+			task.Env = setMapValue(task.Env, key, val)
+		default:
+			err = fmt.Errorf("unknown section %q", section)
+		}
+
+		if err != nil {
+			return fmt.Errorf("line#%d: %w", num, err)
+		}
+	}
+	return s.Err()
+}
+
+func setRunnerValue(cfg *RunnerConfig, key, val string) (err error) {
+	switch key {
+	case "workdir":
+		cfg.Workdir = val
+	case "concurrency":
+		cfg.Concurrency, err = strconv.Atoi(val)
+	case "ignore_err":
+		cfg.IgnoreErr, err = strconv.ParseBool(val)
+	default:
+		err = fmt.Errorf("unknown setting %q", key)
+	}
+	return
+}
+
+func setTaskValue(task *TaskConfig, key, val string) (err error) {
+	switch key {
+	case "cmdline":
+		task.Cmdline = val
+	case "workdir":
+		task.Workdir = val
+	case "depends":
+		task.Depends = strutil.Split(val, ",")
+	case "condition":
+		task.Condition = val
+	case "ignore_err":
+		task.IgnoreErr, err = strconv.ParseBool(val)
+	default:
+		err = fmt.Errorf("unknown task setting %q", key)
+	}
+	return
+}
+
+func setMapValue(mp map[string]string, key, val string) map[string]string {
+	if mp == nil {
+		mp = make(map[string]string)
+	}
+	mp[key] = val
+	return mp
+}
+
+// remove the quotes around the value. eg: "value" => value
+func unquoteValue(val string) string {
+	if ln := len(val); ln > 1 && (val[0] == '"' || val[0] == '\'') && val[ln-1] == val[0] {
+		return val[1 : ln-1]
+	}
+	return val
+}
@@ -0,0 +1,168 @@
+package cmdr_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/sysutil/cmdr"
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/x/assert"
+	"github.com/gookit/goutil/x/ccolor"
+)
+
+func TestLoadRunner_json(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	ccolor.Disable()
+	defer ccolor.RevertColorSupport()
+
+	fPath := filepath.Join(t.TempDir(), "tasks.json")
+	fsutil.MustSave(fPath, `{
+  "env": {"APP_ENV": "test"},
+  "vars": {"name": "inhere", "msg": "hi"},
+  "tasks": [
+    {"id": "greet", "cmdline": "sh -c 'echo $msg $name $APP_ENV'", "vars": {"name": "tom"}},
+    {"id": "skip", "cmdline": "echo skipped", "condition": "test -d /not-exists-dir"},
+    {"id": "after", "cmdline": "echo after $name", "depends": ["greet"]}
+  ]
+}`)
+
+	rr, err := cmdr.LoadRunner(fPath)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, rr.Len())
+
+	task, err := rr.Task("after")
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"greet"}, task.Depends)
+
+	testutil.RewriteStdout()
+	err = rr.Run()
+	out := testutil.RestoreStdout()
+	assert.NoErr(t, err)
+	assert.StrContains(t, out, "hi tom test\n")
+	assert.StrContains(t, out, "after inhere\n")
+	assert.NotContains(t, out, "skipped")
+
+	task, _ = rr.Task("skip")
+	assert.True(t, task.IsSkipped())
+
+	// condition use the runner workdir and env
+	dir := t.TempDir()
+	fsutil.MustSave(dir+"/flag.txt", "ok")
+	fsutil.MustSave(fPath, `{
+  "workdir": "`+dir+`",
+  "env": {"APP_ENV": "test"},
+  "tasks": [
+    {"id": "a", "cmdline": "echo run-a", "condition": "test -f flag.txt"},
+    {"id": "b", "cmdline": "echo run-b", "condition": "sh -c 'test \"$APP_ENV\" = test'"}
+  ]
+}`)
+	rr, err = cmdr.LoadRunner(fPath)
+	assert.NoErr(t, err)
+
+	testutil.RewriteStdout()
+	err = rr.Run()
+	out = testutil.RestoreStdout()
+	assert.NoErr(t, err)
+	assert.StrContains(t, out, "run-a\n")
+	assert.StrContains(t, out, "run-b\n")
+
+	// error cases
+	_, err = cmdr.LoadRunner("tasks.toml")
+	assert.ErrSubMsg(t, err, "not supported tasks file format")
+
+	fsutil.MustSave(fPath, `{"tasks": [{"id": "a", "cmdline": ""}]}`)
+	_, err = cmdr.LoadRunner(fPath)
+	assert.ErrSubMsg(t, err, "cmdline cannot be empty")
+
+	fsutil.MustSave(fPath, `{"tasks": [{"id": "a", "cmdline": "ls"}, {"id": "a", "cmdline": "ls"}]}`)
+	_, err = cmdr.LoadRunner(fPath)
+	assert.ErrSubMsg(t, err, `task ID "a" is repeated`)
+}
+
+func TestDecodeIniTasks(t *testing.T) {
+	cfg := &cmdr.RunnerConfig{}
+	err := cmdr.DecodeIniTasks([]byte(`
+# global settings
+workdir = /tmp
+concurrency = 2
+ignore_err = true
+
+[vars]
+out = "bin/app"
+
+[env]
+CGO_ENABLED = 0
+
+[task.gen]
+cmdline = go generate ./...
+
+; build task
+[task.build]
+cmdline = go build -o $out ./cmd/app
+depends = gen, lint
+condition = prev.success
+
+[task.build.env]
+GOOS = linux
+
+[task.build.vars]
+out = bin/app-linux
+`), cfg)
+
+	assert.NoErr(t, err)
+	assert.Eq(t, "/tmp", cfg.Workdir)
+	assert.Eq(t, 2, cfg.Concurrency)
+	assert.True(t, cfg.IgnoreErr)
+	assert.Eq(t, "bin/app", cfg.Vars["out"])
+	assert.Eq(t, "0", cfg.Env["CGO_ENABLED"])
+	assert.Len(t, cfg.Tasks, 2)
+
+	task := cfg.Tasks[1]
+	assert.Eq(t, "build", task.ID)
+	assert.Eq(t, "go build -o $out ./cmd/app", task.Cmdline)
+	assert.Eq(t, []string{"gen", "lint"}, task.Depends)
+	assert.Eq(t, "prev.success", task.Condition)
+	assert.Eq(t, "linux", task.Env["GOOS"])
+	assert.Eq(t, "bin/app-linux", task.Vars["out"])
+
+	// sub sections of a task after other tasks
+	cfg = &cmdr.RunnerConfig{}
+	err = cmdr.DecodeIniTasks([]byte(`
+[task.a]
+cmdline = echo a
+
+[task.b]
+cmdline = echo b
+
+[task.a.env]
+NAME = a
+`), cfg)
+	assert.NoErr(t, err)
+	assert.Len(t, cfg.Tasks, 2)
+	assert.Eq(t, "a", cfg.Tasks[0].ID)
+	assert.Eq(t, "echo a", cfg.Tasks[0].Cmdline)
+	assert.Eq(t, "a", cfg.Tasks[0].Env["NAME"])
+	assert.Empty(t, cfg.Tasks[1].Env)
+
+	// error cases
+	err = cmdr.DecodeIniTasks([]byte("[task.a\ncmdline = ls"), cfg)
+	assert.ErrSubMsg(t, err, "invalid section at line#1")
+	err = cmdr.DecodeIniTasks([]byte("[task.a]\nno-value"), cfg)
+	assert.ErrSubMsg(t, err, "invalid key-value at line#2")
+	err = cmdr.DecodeIniTasks([]byte("[task.a]\nunknown = val"), cfg)
+	assert.ErrSubMsg(t, err, `line#2: unknown task setting "unknown"`)
+	err = cmdr.DecodeIniTasks([]byte("[other]\nkey = val"), cfg)
+	assert.ErrSubMsg(t, err, `unknown section "other"`)
+
+	// invalid condition
+	_, err = cmdr.NewRunnerFromConfig(&cmdr.RunnerConfig{
+		Tasks: []*cmdr.TaskConfig{{ID: "a", Cmdline: "ls", Condition: "prev.unknown"}},
+	})
+	assert.ErrSubMsg(t, err, `invalid task condition "prev.unknown"`)
+}
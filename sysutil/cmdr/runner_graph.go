@@ -0,0 +1,276 @@
+package cmdr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gookit/goutil/x/ccolor"
+)
+
+// colors for prefix the task output on parallel run
+var prefixColors = []ccolor.Color{
+	ccolor.FgCyan,
+	ccolor.FgGreen,
+	ccolor.FgYellow,
+	ccolor.FgMagenta,
+	ccolor.FgBlue,
+	ccolor.FgLightCyan,
+	ccolor.FgLightGreen,
+	ccolor.FgLightMagenta,
+}
+
+// useGraph check need to run tasks by dependency graph
+func (r *Runner) useGraph() bool {
+	if r.Concurrency > 1 {
+		return true
+	}
+	for _, task := range r.tasks {
+		if len(task.Depends) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// check the task dependencies, returns the dependents of each task.
+func (r *Runner) buildGraph() (map[string][]*Task, map[string]int, error) {
+	children := make(map[string][]*Task, len(r.tasks))
+	inDegree := make(map[string]int, len(r.tasks))
+
+	for _, task := range r.tasks {
+		if _, ok := inDegree[task.ID]; ok {
+			return nil, nil, fmt.Errorf("cmdr: task ID %q is repeated", task.ID)
+		}
+
+		inDegree[task.ID] = 0
+		for _, dep := range task.Depends {
+			if _, ok := r.idMap[dep]; !ok {
+				return nil, nil, fmt.Errorf("cmdr: task %q depends on not exists task %q", task.ID, dep)
+			}
+			children[dep] = append(children[dep], task)
+			inDegree[task.ID]++
+		}
+	}
+
+	// check cycle dependency by topological sort
+	degree := make(map[string]int, len(inDegree))
+	queue := make([]string, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		if degree[task.ID] = inDegree[task.ID]; degree[task.ID] == 0 {
+			queue = append(queue, task.ID)
+		}
+	}
+
+	var visited int
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, child := range children[id] {
+			if degree[child.ID]--; degree[child.ID] == 0 {
+				queue = append(queue, child.ID)
+			}
+		}
+	}
+
+	if visited < len(r.tasks) {
+		var ids []string
+		for _, task := range r.tasks {
+			if degree[task.ID] > 0 {
+				ids = append(ids, task.ID)
+			}
+		}
+		return nil, nil, fmt.Errorf("cmdr: cycle dependency in tasks: %s", strings.Join(ids, ", "))
+	}
+	return children, inDegree, nil
+}
+
+// run tasks by dependency graph, the independent tasks will run in parallel.
+func (r *Runner) runGraph() error {
+	children, inDegree, err := r.buildGraph()
+	if err != nil {
+		return err
+	}
+
+	limit := r.Concurrency
+	if limit < 1 {
+		limit = 1
+	}
+
+	ready := make([]*Task, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		if inDegree[task.ID] == 0 {
+			ready = append(ready, task)
+		}
+	}
+
+	var stop bool
+	var running int
+	started := make(map[string]bool, len(r.tasks))
+	doneCh := make(chan *Task)
+	outMu := new(sync.Mutex)
+
+	for {
+		for !stop && running < limit && len(ready) > 0 {
+			task := ready[0]
+			ready = ready[1:]
+
+			if r.BeforeRun != nil && !r.BeforeRun(r, task) {
+				r.skipTask(task, children)
+				continue
+			}
+			if (task.PrevCond != nil && task.prev != nil && !task.PrevCond(task.prev)) ||
+				(task.Condition != nil && !task.Condition(task)) {
+				r.skipTask(task, children)
+				continue
+			}
+
+			running++
+			started[task.ID] = true
+			go func(task *Task) {
+				r.runGraphTask(task, limit > 1, outMu)
+				doneCh <- task
+			}(task)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		task := <-doneCh
+		running--
+
+		if task.err != nil {
+			r.Errs[task.ID] = task.err
+			ccolor.Errorf("Task %s run error: %s\n", task.ID, task.err)
+
+			// not ignore error, stop run new tasks and skip all dependents.
+			if !task.IgnoreErr && !r.IgnoreErr {
+				stop = true
+				continue
+			}
+		}
+
+		if r.AfterRun != nil && !r.AfterRun(r, task) {
+			stop = true
+			continue
+		}
+		r.prev = task
+		ready = r.releaseDeps(task, children, inDegree, ready)
+	}
+
+	// stopped on error, mark the not run tasks as skipped.
+	if stop {
+		for _, task := range r.tasks {
+			if !started[task.ID] {
+				task.skipped = true
+			}
+		}
+	}
+
+	if len(r.Errs) == 0 {
+		return nil
+	}
+	return r.Errs
+}
+
+// skipTask mark the task and all dependents as skipped, the dependents will not be released.
+func (r *Runner) skipTask(task *Task, children map[string][]*Task) {
+	task.skipped = true
+	for _, child := range children[task.ID] {
+		if !child.skipped {
+			r.skipTask(child, children)
+		}
+	}
+}
+
+// the task is done, release the dependents that all dependencies are done.
+func (r *Runner) releaseDeps(task *Task, children map[string][]*Task, inDegree map[string]int, ready []*Task) []*Task {
+	for _, child := range children[task.ID] {
+		child.prev = task
+		if inDegree[child.ID]--; inDegree[child.ID] == 0 {
+			ready = append(ready, child)
+		}
+	}
+	return ready
+}
+
+func (r *Runner) runGraphTask(task *Task, parallel bool, outMu *sync.Mutex) {
+	if r.DryRun {
+		ccolor.Infof("DRY-RUN: task %s execute completed\n", task.ID)
+		return
+	}
+
+	r.prepareTask(task)
+
+	// prefix the output by task ID on parallel run
+	var pws []*prefixWriter
+	if parallel && !r.NoPrefix {
+		prefix := prefixColors[task.index%len(prefixColors)].Render("["+task.ID+"]") + " "
+		cmd := task.Cmd
+
+		if cmd.Stdout == os.Stdout || cmd.Stdout == os.Stderr {
+			pw := &prefixWriter{mu: outMu, out: cmd.Stdout, prefix: prefix}
+			pws = append(pws, pw)
+			if cmd.Stderr == cmd.Stdout {
+				cmd.Stderr = pw
+			}
+			cmd.Stdout = pw
+		}
+		if cmd.Stderr == os.Stdout || cmd.Stderr == os.Stderr {
+			pw := &prefixWriter{mu: outMu, out: cmd.Stderr, prefix: prefix}
+			pws = append(pws, pw)
+			cmd.Stderr = pw
+		}
+	}
+
+	task.err = task.RunWith(r.Params)
+	for _, pw := range pws {
+		pw.Flush()
+	}
+}
+
+// prefixWriter add prefix for each line, and write whole lines with a shared lock.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+// Write implements io.Writer
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	idx := bytes.LastIndexByte(w.buf, '\n')
+	if idx < 0 {
+		return len(p), nil
+	}
+
+	lines := w.buf[:idx+1]
+	var sb strings.Builder
+	for len(lines) > 0 {
+		pos := bytes.IndexByte(lines, '\n')
+		sb.WriteString(w.prefix)
+		sb.Write(lines[:pos+1])
+		lines = lines[pos+1:]
+	}
+	w.buf = append(w.buf[:0], w.buf[idx+1:]...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.out, sb.String()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush the remaining data without newline
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		_, _ = w.Write([]byte{'\n'})
+	}
+}
@@ -3,10 +3,14 @@ package cmdr_test
 import (
 	"bytes"
 	"fmt"
+	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/gookit/goutil/sysutil/cmdr"
+	"github.com/gookit/goutil/testutil"
 	"github.com/gookit/goutil/x/assert"
+	"github.com/gookit/goutil/x/ccolor"
 )
 
 func TestRunner_Run(t *testing.T) {
@@ -40,3 +44,126 @@ func TestRunner_Run(t *testing.T) {
 
 	fmt.Println(buf.String())
 }
+
+func TestRunner_graph(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	var mu sync.Mutex
+	var order []string
+	newTask := func(id string, deps ...string) *cmdr.Task {
+		return &cmdr.Task{
+			ID:      id,
+			Cmd:     cmdr.NewCmd("sh", "-c", "sleep 0.05"),
+			Depends: deps,
+		}
+	}
+
+	rr := cmdr.NewRunner(func(rr *cmdr.Runner) {
+		rr.AfterRun = func(r *cmdr.Runner, t *cmdr.Task) bool {
+			mu.Lock()
+			order = append(order, t.ID)
+			mu.Unlock()
+			return true
+		}
+	})
+	rr.WithConcurrency(3).Add(
+		newTask("build", "gen", "deps"),
+		newTask("gen"),
+		newTask("deps"),
+		newTask("test", "build"),
+		newTask("lint", "gen"),
+	)
+
+	err := rr.Run()
+	assert.NoErr(t, err)
+	assert.Len(t, order, 5)
+	assert.Eq(t, "test", order[4])
+	assert.Contains(t, order[:2], "gen")
+	assert.Contains(t, order[:2], "deps")
+
+	// cycle dependency
+	rr = cmdr.NewRunner().Add(newTask("a", "b"), newTask("b", "a"), newTask("c"))
+	err = rr.Run()
+	assert.ErrSubMsg(t, err, "cycle dependency in tasks: a, b")
+
+	// not exists
+	err = cmdr.NewRunner().Add(newTask("a", "not-exist")).Run()
+	assert.ErrSubMsg(t, err, `depends on not exists task "not-exist"`)
+}
+
+func TestRunner_graphOnErr(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	// stop on error, skip dependents
+	rr := cmdr.NewRunner()
+	rr.Add(
+		&cmdr.Task{ID: "fail", Cmd: cmdr.NewCmd("sh", "-c", "exit 2")},
+		&cmdr.Task{ID: "after", Cmd: cmdr.NewCmd("true"), Depends: []string{"fail"}},
+	)
+	err := rr.Run()
+	assert.Err(t, err)
+	assert.Err(t, rr.Errs["fail"])
+	after, _ := rr.Task("after")
+	assert.Nil(t, after.Cmd.ProcessState)
+	assert.True(t, after.IsSkipped())
+
+	// skipped task will not release the dependents
+	rr = cmdr.NewRunner()
+	rr.Add(
+		&cmdr.Task{ID: "skip", Cmd: cmdr.NewCmd("true"), Condition: func(t *cmdr.Task) bool { return false }},
+		&cmdr.Task{ID: "after", Cmd: cmdr.NewCmd("true"), Depends: []string{"skip"}},
+		&cmdr.Task{ID: "last", Cmd: cmdr.NewCmd("true"), Depends: []string{"after"}},
+		&cmdr.Task{ID: "other", Cmd: cmdr.NewCmd("true")},
+	)
+	assert.NoErr(t, rr.Run())
+	for _, id := range []string{"skip", "after", "last"} {
+		task, _ := rr.Task(id)
+		assert.True(t, task.IsSkipped(), id)
+		assert.Nil(t, task.Cmd.ProcessState, id)
+	}
+	other, _ := rr.Task("other")
+	assert.False(t, other.IsSkipped())
+	assert.True(t, other.Cmd.ProcessState.Success())
+
+	// continue on error
+	rr = cmdr.NewRunner()
+	rr.Add(
+		&cmdr.Task{ID: "fail", Cmd: cmdr.NewCmd("sh", "-c", "exit 2"), IgnoreErr: true},
+		(&cmdr.Task{ID: "after", Cmd: cmdr.NewCmd("true")}).DependOn("fail"),
+	)
+	err = rr.Run()
+	assert.Err(t, err)
+	assert.Len(t, rr.Errs, 1)
+	after, _ = rr.Task("after")
+	assert.True(t, after.Cmd.ProcessState.Success())
+}
+
+func TestRunner_parallelPrefix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	ccolor.Disable()
+	defer ccolor.RevertColorSupport()
+
+	rr := cmdr.NewRunner().WithConcurrency(2)
+	rr.Add(
+		&cmdr.Task{ID: "t1", Cmd: cmdr.NewCmd("sh", "-c", "echo hello; printf no-newline")},
+		&cmdr.Task{ID: "t2", Cmd: cmdr.NewCmd("echo", "world")},
+	)
+
+	testutil.RewriteStdout()
+	err := rr.Run()
+	out := testutil.RestoreStdout()
+	assert.NoErr(t, err)
+	assert.StrContains(t, out, "[t1] hello\n")
+	assert.StrContains(t, out, "[t1] no-newline\n")
+	assert.StrContains(t, out, "[t2] world\n")
+}
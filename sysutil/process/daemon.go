@@ -0,0 +1,205 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+)
+
+// DaemonEnvKey the ENV name for mark the process is started by Daemon()
+const DaemonEnvKey = "GOUTIL_DAEMON_PROCESS"
+
+// the ENV name for pass the pid file to the daemon process
+const daemonPidFileKey = "GOUTIL_DAEMON_PID_FILE"
+
+// daemonLockTimeout wait for the daemon process take over the pid file lock
+const daemonLockTimeout = 5 * time.Second
+
+// daemonUnlock hold the pid file lock in the daemon process, avoid the file closed by GC.
+var daemonUnlock fsutil.UnlockFunc
+
+// DaemonOption for Daemon()
+type DaemonOption struct {
+	// PidFile path for save the daemon PID. optional
+	//
+	// The daemon process will lock the pid file for its lifetime, see PidFile.Lock().
+	// Will refuse to start if the pid file is locked or the PID in file is a running process.
+	PidFile string
+	// Stdout, Stderr log file path for redirect the output. default is os.DevNull
+	//
+	// Can use the same file for Stdout and Stderr.
+	Stdout, Stderr string
+	// Workdir for the daemon process. default is current workdir
+	Workdir string
+	// Args for re-exec the program. default is os.Args[1:]
+	Args []string
+	// Env append to the daemon process ENV.
+	Env []string
+}
+
+// IsDaemon check current process is started by Daemon()
+func IsDaemon() bool {
+	return os.Getenv(DaemonEnvKey) == "1"
+}
+
+// Daemon detach current program to background: re-exec the program in a new session,
+// redirect the stdio to log files, and write the PID to PidFile.
+//
+// Returns the started daemon process in the parent, the caller should exit.
+// In the daemon process, will take over the pid file lock, then returns nil process and nil error.
+//
+// Usage:
+//
+//	proc, err := process.Daemon(&process.DaemonOption{PidFile: "app.pid", Stdout: "app.log", Stderr: "app.log"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if proc != nil {
+//		fmt.Println("daemon started, PID:", proc.Pid)
+//		os.Exit(0)
+//	}
+//
+//	// in daemon process, run the service ...
+func Daemon(opt *DaemonOption) (*os.Process, error) {
+	if IsDaemon() {
+		return nil, takeDaemonPidFile()
+	}
+	if opt == nil {
+		opt = &DaemonOption{}
+	}
+
+	var pf *PidFile
+	var unlock fsutil.UnlockFunc
+	if opt.PidFile != "" {
+		pidFile, err := filepath.Abs(opt.PidFile)
+		if err != nil {
+			return nil, err
+		}
+		if pf = NewPidFile(pidFile); pf.IsRunning() {
+			return nil, fmt.Errorf("daemon is running, PID %d in file %s", pf.PID(), opt.PidFile)
+		}
+
+		// lock the pid file until the daemon process take over it
+		if unlock, err = pf.Lock(); err != nil {
+			return nil, fmt.Errorf("daemon is running, %w", err)
+		}
+		defer func() {
+			if unlock != nil {
+				_ = unlock()
+			}
+		}()
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	args := opt.Args
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	cmd := exec.Command(exe, args...)
+	cmd.Dir = opt.Workdir
+	cmd.Env = append(append(os.Environ(), opt.Env...), DaemonEnvKey+"=1")
+	if pf != nil {
+		cmd.Env = append(cmd.Env, daemonPidFileKey+"="+pf.File())
+	}
+	setDetached(cmd)
+
+	closers, err := setDaemonStdio(cmd, opt)
+	defer func() {
+		for _, cl := range closers {
+			_ = cl.Close()
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	if pf != nil {
+		// release the lock, and wait the daemon process take over it.
+		_ = unlock()
+		unlock = nil
+
+		deadline := time.Now().Add(daemonLockTimeout)
+		for fsutil.LockHolder(pf.File()) != cmd.Process.Pid {
+			if time.Now().After(deadline) {
+				_ = cmd.Process.Kill()
+				return nil, fmt.Errorf("the daemon process %d not take over the pid file %s", cmd.Process.Pid, opt.PidFile)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	return cmd.Process, nil
+}
+
+// takeDaemonPidFile lock the pid file in the daemon process, hold it until the process exit.
+func takeDaemonPidFile() error {
+	pidFile := os.Getenv(daemonPidFileKey)
+	if pidFile == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonLockTimeout)
+	defer cancel()
+
+	unlock, err := fsutil.LockContext(ctx, pidFile)
+	if err != nil {
+		return fmt.Errorf("lock the pid file %s error: %w", pidFile, err)
+	}
+
+	daemonUnlock = unlock
+	// dont pass to the sub processes
+	return os.Unsetenv(daemonPidFileKey)
+}
+
+// open the log files for the daemon stdio
+func setDaemonStdio(cmd *exec.Cmd, opt *DaemonOption) ([]io.Closer, error) {
+	var closers []io.Closer
+	open := func(fPath string, flag int) (*os.File, error) {
+		var f *os.File
+		var err error
+		if fPath == "" || fPath == os.DevNull {
+			f, err = os.OpenFile(os.DevNull, flag, 0)
+		} else {
+			f, err = fsutil.OpenAppendFile(fPath)
+		}
+
+		if err == nil {
+			closers = append(closers, f)
+		}
+		return f, err
+	}
+
+	stdin, err := open("", os.O_RDONLY)
+	if err != nil {
+		return closers, err
+	}
+
+	stdout, err := open(opt.Stdout, os.O_WRONLY)
+	if err != nil {
+		return closers, err
+	}
+
+	stderr := stdout
+	if opt.Stderr != opt.Stdout {
+		if stderr, err = open(opt.Stderr, os.O_WRONLY); err != nil {
+			return closers, err
+		}
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+	return closers, nil
+}
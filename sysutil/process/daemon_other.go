@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package process
+
+import (
+	"os"
+	"os/exec"
+)
+
+// default signals for Supervisor forward to the child process
+var defaultSignals = []os.Signal{os.Interrupt}
+
+// setDetached on the OS is not supported. eg: plan9, js/wasm
+func setDetached(_ *exec.Cmd) {}
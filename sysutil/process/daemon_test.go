@@ -0,0 +1,95 @@
+package process_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/sysutil/cmdr"
+	"github.com/gookit/goutil/sysutil/process"
+	"github.com/gookit/goutil/x/assert"
+)
+
+// run as the daemon process by TestDaemon
+func TestDaemon_child(t *testing.T) {
+	if !process.IsDaemon() {
+		t.Skip("only run in daemon process")
+		return
+	}
+
+	proc, err := process.Daemon(nil)
+	fmt.Println("daemon child:", proc == nil && err == nil, os.Getpid())
+}
+
+func TestDaemon(t *testing.T) {
+	if runtime.GOOS == "windows" || process.IsDaemon() {
+		t.Skip("skip on windows")
+		return
+	}
+
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "app.pid")
+	logFile := filepath.Join(dir, "app.log")
+
+	proc, err := process.Daemon(&process.DaemonOption{
+		PidFile: pidFile,
+		Stdout:  logFile,
+		Stderr:  logFile,
+		Args:    []string{"-test.run=^TestDaemon_child$", "-test.v"},
+	})
+	assert.NoErr(t, err)
+	assert.NotNil(t, proc)
+
+	pf := process.NewPidFile(pidFile)
+	assert.Eq(t, proc.Pid, pf.PID())
+
+	// wait the daemon exit
+	_, err = proc.Wait()
+	assert.NoErr(t, err)
+	out := fsutil.ReadString(logFile)
+	assert.StrContains(t, out, "daemon child: true "+strconv.Itoa(proc.Pid))
+
+	// refuse start on the daemon is running
+	pf = process.NewPidFile(pidFile)
+	pf.SetPID(os.Getpid())
+	assert.NoErr(t, pf.Save())
+	_, err = process.Daemon(&process.DaemonOption{PidFile: pidFile})
+	assert.ErrSubMsg(t, err, "daemon is running")
+	assert.NoErr(t, pf.Remove())
+	assert.False(t, pf.Exists())
+}
+
+func TestSupervisor_restart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+		return
+	}
+
+	var starts, exits int
+	sv := process.NewSupervisor(cmdr.NewCmd("sh", "-c", "exit 3"), func(s *process.Supervisor) {
+		s.MaxRestarts = 2
+		s.MinBackoff = 10 * time.Millisecond
+		s.OnStart = func(c *cmdr.Cmd) { starts++ }
+		s.OnExit = func(c *cmdr.Cmd, err error, restarts int) { exits++ }
+	})
+
+	err := sv.Run()
+	assert.ErrSubMsg(t, err, "too many restarts(2 in 1m0s)")
+	assert.ErrSubMsg(t, err, "exit status 3")
+	assert.Eq(t, 3, starts)
+	assert.Eq(t, 3, exits)
+	assert.Eq(t, 0, sv.PID())
+
+	// exit with code 0
+	starts = 0
+	sv = process.NewSupervisor(cmdr.NewCmd("true"), func(s *process.Supervisor) {
+		s.OnStart = func(c *cmdr.Cmd) { starts++ }
+	})
+	assert.NoErr(t, sv.Run())
+	assert.Eq(t, 1, starts)
+}
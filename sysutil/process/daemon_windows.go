@@ -0,0 +1,20 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// default signals for Supervisor forward to the child process
+var defaultSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// start the process without console, detach from the terminal.
+func setDetached(c *exec.Cmd) {
+	if c.SysProcAttr == nil {
+		c.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	c.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS
+}
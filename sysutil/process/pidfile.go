@@ -1,6 +1,8 @@
 package process
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/gookit/goutil/fsutil"
@@ -12,7 +14,8 @@ import (
 type PidFile struct {
 	pid  int
 	file string
-	// body string
+	// mark the pid file is locked by Lock()
+	locked bool
 }
 
 // NewPidFile instance
@@ -59,12 +62,82 @@ func (pf *PidFile) SetPID(val int) int {
 	return pf.pid
 }
 
-// Save PID value to file
+// Save PID value to file.
+//
+// If the pid file is locked by Lock(), will write the file in place, so the lock is kept.
+// Otherwise, will write to a temp file then rename it, the readers never see a half-written file.
 func (pf *PidFile) Save() error {
 	if pf.pid < 1 {
 		return nil
 	}
+	if !pf.locked {
+		return fsutil.AtomicWrite(pf.file, pf.String())
+	}
+
+	fh, err := os.OpenFile(pf.file, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
 
-	_, err := fsutil.PutContents(pf.file, pf.String())
+	// write first then truncate, so the file is never empty.
+	bs := []byte(pf.String())
+	if _, err = fh.WriteAt(bs, 0); err == nil {
+		err = fh.Truncate(int64(len(bs)))
+	}
+	if err1 := fh.Close(); err1 != nil && err == nil {
+		err = err1
+	}
 	return err
 }
+
+// Remove the pid file
+func (pf *PidFile) Remove() error {
+	return fsutil.RmFileIfExist(pf.file)
+}
+
+// IsRunning check the PID in file is a running process.
+func (pf *PidFile) IsRunning() bool {
+	pid := pf.PID()
+	return pid > 0 && Exists(pid)
+}
+
+// IsStale check the pid file is stale: the PID in file is not a running process.
+// eg: the pid file left by a crashed process.
+func (pf *PidFile) IsStale() bool {
+	pid := fsutil.LockHolder(pf.file)
+	return pid > 0 && !Exists(pid)
+}
+
+// Lock the pid file and write current PID to it. see fsutil.TryLock()
+//
+// Will return error if the pid file is locked or the PID in file is a running process.
+// The stale pid file will be taken over.
+//
+// Usage:
+//
+//	pf := process.NewPidFile("path/to/app.pid")
+//	unlock, err := pf.Lock()
+//	if err != nil {
+//		return err // other instance is running
+//	}
+//	defer unlock()
+func (pf *PidFile) Lock() (fsutil.UnlockFunc, error) {
+	if pid := fsutil.LockHolder(pf.file); pid > 0 && pid != os.Getpid() && Exists(pid) {
+		return nil, fmt.Errorf("pid file %s is used by running process %d", pf.file, pid)
+	}
+
+	unlock, err := fsutil.TryLock(pf.file)
+	if err != nil {
+		if errors.Is(err, fsutil.ErrLocked) {
+			return nil, fmt.Errorf("pid file %s is locked by process %d", pf.file, fsutil.LockHolder(pf.file))
+		}
+		return nil, err
+	}
+
+	pf.pid = os.Getpid()
+	pf.locked = true
+	return func() error {
+		pf.locked = false
+		return unlock()
+	}, nil
+}
@@ -0,0 +1,50 @@
+package process_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/sysutil/process"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestPidFile_Lock(t *testing.T) {
+	pf := process.NewPidFile(filepath.Join(t.TempDir(), "app.pid"))
+	assert.False(t, pf.Exists())
+	assert.False(t, pf.IsStale())
+
+	unlock, err := pf.Lock()
+	assert.NoErr(t, err)
+	assert.Eq(t, os.Getpid(), pf.PID())
+	assert.Eq(t, os.Getpid(), fsutil.LockHolder(pf.File()))
+	assert.False(t, pf.IsStale())
+
+	// lock again
+	_, err = process.NewPidFile(pf.File()).Lock()
+	assert.Err(t, err)
+
+	// save will keep the lock
+	pf.SetPID(1234567)
+	assert.NoErr(t, pf.Save())
+	pf.SetPID(12)
+	assert.NoErr(t, pf.Save())
+	assert.Eq(t, "12", fsutil.ReadString(pf.File()))
+	_, err = process.NewPidFile(pf.File()).Lock()
+	assert.Err(t, err)
+	assert.NoErr(t, unlock())
+
+	// save on not locked
+	pf.SetPID(345)
+	assert.NoErr(t, pf.Save())
+	assert.Eq(t, "345", fsutil.ReadString(pf.File()))
+
+	// stale pid file
+	fsutil.Must2(fsutil.PutContents(pf.File(), "999999999"))
+	assert.True(t, pf.IsStale())
+	unlock, err = pf.Lock()
+	assert.NoErr(t, err)
+	assert.False(t, pf.IsStale())
+	assert.NoErr(t, unlock())
+}
@@ -0,0 +1,146 @@
+package process
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ProcessInfo of a running process. see ReadInfo()
+type ProcessInfo struct {
+	PID  int
+	PPID int
+	// Name of the process, max 15 chars on Linux. eg: "bash"
+	Name    string
+	Cmdline []string
+	// Exe the executable file path. empty if not permitted.
+	Exe string
+	// Cwd the current workdir. empty if not permitted.
+	Cwd string
+	// State of the process. eg: R(running), S(sleeping), D(disk sleep), Z(zombie), T(stopped)
+	State     string
+	StartTime time.Time
+	// RSS resident set size, VMS virtual memory size. unit: bytes
+	RSS, VMS uint64
+	// UserTime, SystemTime the CPU times of the process.
+	UserTime, SystemTime time.Duration
+	// NumFDs count of the opened files. -1 if not permitted.
+	NumFDs     int
+	NumThreads int
+	// Env of the process. nil if not permitted.
+	Env []string
+}
+
+// CPUTime total CPU time of the process
+func (p *ProcessInfo) CPUTime() time.Duration { return p.UserTime + p.SystemTime }
+
+// IsZombie check the process is zombie
+func (p *ProcessInfo) IsZombie() bool { return p.State == "Z" }
+
+// MatchName check the process name or the executable base name is equals to name
+func (p *ProcessInfo) MatchName(name string) bool {
+	if p.Name == name {
+		return true
+	}
+	if len(p.Cmdline) > 0 && filepath.Base(p.Cmdline[0]) == name {
+		return true
+	}
+	return p.Exe != "" && filepath.Base(p.Exe) == name
+}
+
+func errNotFound(pid int) error {
+	return fmt.Errorf("process %d is not exists", pid)
+}
+
+// ProcessFilter for filter processes on List(). return true to keep.
+type ProcessFilter func(p *ProcessInfo) bool
+
+// FindByName find all processes that name matched. see ProcessInfo.MatchName()
+func FindByName(name string) ([]*ProcessInfo, error) {
+	return List(func(p *ProcessInfo) bool {
+		return p.MatchName(name)
+	})
+}
+
+// FindByCmdline find all processes that cmdline contains the keywords.
+func FindByCmdline(keywords string) ([]*ProcessInfo, error) {
+	return List(func(p *ProcessInfo) bool {
+		return strings.Contains(strings.Join(p.Cmdline, " "), keywords)
+	})
+}
+
+// Children get the direct child processes of the pid
+func Children(pid int) ([]*ProcessInfo, error) {
+	return List(func(p *ProcessInfo) bool {
+		return p.PPID == pid
+	})
+}
+
+// ProcessNode of the process tree. see Tree()
+type ProcessNode struct {
+	*ProcessInfo
+	Children []*ProcessNode
+}
+
+// Walk the node and all descendants, depth-first. stop walk on fn returns false.
+func (n *ProcessNode) Walk(fn func(n *ProcessNode, depth int) bool) {
+	n.walk(fn, 0)
+}
+
+func (n *ProcessNode) walk(fn func(n *ProcessNode, depth int) bool, depth int) bool {
+	if !fn(n, depth) {
+		return false
+	}
+
+	for _, child := range n.Children {
+		if !child.walk(fn, depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// PIDs of the node and all descendants.
+func (n *ProcessNode) PIDs() []int {
+	var pids []int
+	n.Walk(func(n *ProcessNode, _ int) bool {
+		pids = append(pids, n.PID)
+		return true
+	})
+	return pids
+}
+
+// Tree get the process tree of the pid, includes all descendants.
+func Tree(pid int) (*ProcessNode, error) {
+	list, err := List(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *ProcessNode
+	children := make(map[int][]*ProcessInfo, len(list))
+	for _, p := range list {
+		if p.PID == pid {
+			root = &ProcessNode{ProcessInfo: p}
+		} else {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+
+	if root == nil {
+		return nil, errNotFound(pid)
+	}
+
+	queue := []*ProcessNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, p := range children[node.PID] {
+			child := &ProcessNode{ProcessInfo: p}
+			node.Children = append(node.Children, child)
+			queue = append(queue, child)
+		}
+	}
+	return root, nil
+}
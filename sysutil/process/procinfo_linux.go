@@ -0,0 +1,207 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the proc filesystem root path
+var procRoot = "/proc"
+
+// clockTicks the USER_HZ for convert the CPU times in /proc/<pid>/stat.
+// it is 100 on almost all Linux platforms.
+const clockTicks = 100
+
+var (
+	bootTime     time.Time
+	bootTimeOnce sync.Once
+)
+
+// ReadInfo read the process info from /proc/<pid>
+//
+// Usage:
+//
+//	info, err := process.ReadInfo(os.Getpid())
+//	fmt.Println(info.Name, info.RSS, info.CPUTime())
+func ReadInfo(pid int) (*ProcessInfo, error) {
+	dir := filepath.Join(procRoot, strconv.Itoa(pid))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errNotFound(pid)
+		}
+		return nil, err
+	}
+
+	p := &ProcessInfo{PID: pid, NumFDs: -1}
+	if err = parseProcStat(p, string(stat)); err != nil {
+		return nil, fmt.Errorf("parse %s/stat error: %w", dir, err)
+	}
+
+	if bs, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		p.Cmdline = splitNulStrings(bs)
+	}
+	if bs, err := os.ReadFile(filepath.Join(dir, "environ")); err == nil {
+		p.Env = splitNulStrings(bs)
+	}
+
+	// need permission for read the links
+	p.Exe, _ = os.Readlink(filepath.Join(dir, "exe"))
+	p.Cwd, _ = os.Readlink(filepath.Join(dir, "cwd"))
+	if fds, err := os.ReadDir(filepath.Join(dir, "fd")); err == nil {
+		p.NumFDs = len(fds)
+	}
+	return p, nil
+}
+
+// List all running processes, sorted by PID. filter is optional.
+//
+// Usage:
+//
+//	list, err := process.List(func(p *process.ProcessInfo) bool {
+//		return p.RSS > 100*1024*1024
+//	})
+func List(filter ProcessFilter) ([]*ProcessInfo, error) {
+	pids, err := listPIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*ProcessInfo, 0, len(pids))
+	for _, pid := range pids {
+		p, err := ReadInfo(pid)
+		// the process may have exited, skip it
+		if err != nil {
+			continue
+		}
+
+		if filter == nil || filter(p) {
+			list = append(list, p)
+		}
+	}
+	return list, nil
+}
+
+// find the first PID that the name contains keywords, will skip current process.
+// only read the /proc/<pid>/stat, lighter than List().
+func findPIDByName(keywords string) (int, error) {
+	pids, err := listPIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	self := os.Getpid()
+	for _, pid := range pids {
+		if pid == self {
+			continue
+		}
+
+		stat, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "stat"))
+		// the process may have exited, skip it
+		if err != nil {
+			continue
+		}
+
+		s := string(stat)
+		start, end := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+		if start >= 0 && end > start && strings.Contains(s[start+1:end], keywords) {
+			return pid, nil
+		}
+	}
+	return 0, nil
+}
+
+// list all PIDs in the /proc, sorted by PID.
+func listPIDs() ([]int, error) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	pids := make([]int, 0, len(entries))
+	for _, ent := range entries {
+		if pid, err := strconv.Atoi(ent.Name()); err == nil && ent.IsDir() {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Ints(pids)
+	return pids, nil
+}
+
+// parse /proc/<pid>/stat contents. see `man 5 proc`
+func parseProcStat(p *ProcessInfo, stat string) error {
+	// the name may contain spaces and parentheses. eg: "1 (my app) S 0 ..."
+	start, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if start < 0 || end < start {
+		return errors.New("invalid stat format")
+	}
+
+	p.Name = stat[start+1 : end]
+	// fields start from the state(field 3)
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 22 {
+		return errors.New("invalid stat fields count")
+	}
+
+	nums := make(map[int]uint64, 7)
+	for _, idx := range []int{1, 11, 12, 17, 19, 20, 21} {
+		val, err := strconv.ParseUint(fields[idx], 10, 64)
+		if err != nil {
+			return err
+		}
+		nums[idx] = val
+	}
+
+	p.State = fields[0]
+	p.PPID = int(nums[1])
+	p.UserTime = ticksToDuration(nums[11])
+	p.SystemTime = ticksToDuration(nums[12])
+	p.NumThreads = int(nums[17])
+	p.VMS = nums[20]
+	p.RSS = nums[21] * uint64(os.Getpagesize())
+
+	if bt := readBootTime(); !bt.IsZero() {
+		p.StartTime = bt.Add(ticksToDuration(nums[19]))
+	}
+	return nil
+}
+
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / clockTicks
+}
+
+// read the system boot time from /proc/stat
+func readBootTime() time.Time {
+	bootTimeOnce.Do(func() {
+		bs, err := os.ReadFile(filepath.Join(procRoot, "stat"))
+		if err != nil {
+			return
+		}
+
+		for _, line := range strings.Split(string(bs), "\n") {
+			if strings.HasPrefix(line, "btime ") {
+				if sec, err := strconv.ParseInt(strings.TrimSpace(line[6:]), 10, 64); err == nil {
+					bootTime = time.Unix(sec, 0)
+				}
+				break
+			}
+		}
+	})
+	return bootTime
+}
+
+func splitNulStrings(bs []byte) []string {
+	bs = bytes.TrimRight(bs, "\x00")
+	if len(bs) == 0 {
+		return nil
+	}
+	return strings.Split(string(bs), "\x00")
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package process
+
+import "errors"
+
+var errNotSupported = errors.New("process: read process info is only supported on Linux")
+
+// ReadInfo read the process info. only supported on Linux.
+func ReadInfo(_ int) (*ProcessInfo, error) {
+	return nil, errNotSupported
+}
+
+func findPIDByName(_ string) (int, error) {
+	return 0, errNotSupported
+}
+
+// List all running processes. only supported on Linux.
+func List(_ ProcessFilter) ([]*ProcessInfo, error) {
+	return nil, errNotSupported
+}
@@ -0,0 +1,112 @@
+package process_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/sysutil/process"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestReadInfo(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		_, err := process.ReadInfo(os.Getpid())
+		assert.ErrSubMsg(t, err, "only supported on Linux")
+		return
+	}
+
+	p, err := process.ReadInfo(os.Getpid())
+	assert.NoErr(t, err)
+	assert.Eq(t, os.Getpid(), p.PID)
+	assert.Eq(t, os.Getppid(), p.PPID)
+	assert.NotEmpty(t, p.Name)
+	assert.NotEmpty(t, p.Cmdline)
+	assert.NotEmpty(t, p.State)
+	assert.NotEmpty(t, p.Env)
+	assert.True(t, p.RSS > 0)
+	assert.True(t, p.VMS >= p.RSS)
+	assert.True(t, p.NumThreads > 0)
+	assert.True(t, p.NumFDs > 0)
+	assert.True(t, p.StartTime.Before(time.Now()))
+	assert.False(t, p.IsZombie())
+
+	exe, _ := os.Executable()
+	assert.Eq(t, exe, p.Exe)
+	wd, _ := os.Getwd()
+	assert.Eq(t, wd, p.Cwd)
+
+	_, err = process.ReadInfo(999999999)
+	assert.ErrSubMsg(t, err, "process 999999999 is not exists")
+}
+
+func TestChildren_Tree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("only supported on Linux")
+		return
+	}
+
+	// sh -> sleep
+	cmd := exec.Command("sh", "-c", "sleep 10 & wait")
+	assert.NoErr(t, cmd.Start())
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	var children []*process.ProcessInfo
+	for i := 0; i < 50; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if children, _ = process.Children(cmd.Process.Pid); len(children) > 0 {
+			break
+		}
+	}
+	assert.Len(t, children, 1)
+	assert.Eq(t, "sleep", children[0].Name)
+	assert.Eq(t, []string{"sleep", "10"}, children[0].Cmdline)
+	sleepPid := children[0].PID
+	defer func() {
+		_ = process.Kill(sleepPid, 9)
+	}()
+
+	list, err := process.FindByName("sleep")
+	assert.NoErr(t, err)
+	assert.NotEmpty(t, list)
+	assert.True(t, process.ExistsByName("sleep", false))
+	assert.True(t, process.ExistsByName("slee", true))
+	assert.False(t, process.ExistsByName("never-exist-process", true))
+
+	// skip current process
+	assert.True(t, process.PIDByName("sleep") > 0)
+	self, err := process.ReadInfo(os.Getpid())
+	assert.NoErr(t, err)
+	assert.NotEq(t, os.Getpid(), process.PIDByName(self.Name))
+	assert.Eq(t, 0, process.PIDByName("never-exist-process"))
+
+	var pids []int
+	for _, p := range list {
+		pids = append(pids, p.PID)
+	}
+	assert.Contains(t, pids, sleepPid)
+
+	list, err = process.FindByCmdline("sleep 10")
+	assert.NoErr(t, err)
+	assert.NotEmpty(t, list)
+
+	tree, err := process.Tree(os.Getpid())
+	assert.NoErr(t, err)
+	assert.Eq(t, os.Getpid(), tree.PID)
+	assert.Eq(t, []int{os.Getpid(), cmd.Process.Pid, sleepPid}, tree.PIDs())
+
+	depths := map[int]int{}
+	tree.Walk(func(n *process.ProcessNode, depth int) bool {
+		depths[n.PID] = depth
+		return true
+	})
+	assert.Eq(t, 2, depths[sleepPid])
+
+	_, err = process.Tree(999999999)
+	assert.Err(t, err)
+}
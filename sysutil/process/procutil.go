@@ -5,9 +5,9 @@ import (
 	"errors"
 	"os"
 	"os/exec"
+	"strings"
 	"syscall"
 
-	"github.com/gookit/goutil/byteutil"
 	"github.com/gookit/goutil/strutil"
 )
 
@@ -31,18 +31,30 @@ func ProcInfo(pid int) (*os.Process, error) {
 	return os.FindProcess(pid)
 }
 
-// PIDByName get PID by process name match(by pgrep)
+// PIDByName get PID by process name match. returns the first matched PID, will skip current process.
+//
+// On Linux, find by read /proc, other OS use pgrep. see FindByName() for get all matched.
 func PIDByName(keywords string) int {
+	pid, err := findPIDByName(keywords)
+	if err == nil {
+		return pid
+	}
+
 	// pgrep keywords
 	binFile := "pgrep"
-	_, err := exec.LookPath(binFile)
+	_, err = exec.LookPath(binFile)
 	if err == nil {
 		output, err := exec.Command(binFile, keywords).Output()
 		if err != nil {
 			return 0
 		}
 
-		return strutil.SafeInt(string(byteutil.FirstLine(output)))
+		self := os.Getpid()
+		for _, line := range strings.Split(string(output), "\n") {
+			if pid = strutil.SafeInt(strings.TrimSpace(line)); pid > 0 && pid != self {
+				return pid
+			}
+		}
 	}
 
 	return 0
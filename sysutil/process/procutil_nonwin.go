@@ -2,16 +2,27 @@
 
 package process
 
-import "syscall"
+import (
+	"strings"
+	"syscall"
+)
 
 // Kill a process by pid
 func Kill(pid int, signal syscall.Signal) error {
 	return syscall.Kill(pid, signal)
 }
 
-// ExistsByName check process running by given name
+// ExistsByName check process running by given name. only supported on Linux.
+//
+// fuzzyMatch: check the process name contains the name.
 func ExistsByName(name string, fuzzyMatch bool) bool {
-	return false // TODO
+	list, err := List(func(p *ProcessInfo) bool {
+		if fuzzyMatch {
+			return strings.Contains(p.Name, name)
+		}
+		return p.MatchName(name)
+	})
+	return err == nil && len(list) > 0
 }
 
 // StopByName Stop process based on process name.
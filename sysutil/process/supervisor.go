@@ -0,0 +1,266 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gookit/goutil/sysutil/cmdr"
+)
+
+// Supervisor run a child command, and restart it with backoff on crash.
+//
+// The restarts are limited by MaxRestarts in Window, the signals are forwarded to the child.
+//
+// Usage:
+//
+//	sv := process.NewSupervisor(cmdr.NewCmd("./my-server", "--port", "8080").ToOSStdoutStderr())
+//	err := sv.Run() // blocking until the child exited normally, stopped or too many restarts.
+type Supervisor struct {
+	cmd *cmdr.Cmd
+	// current running command
+	cur    *cmdr.Cmd
+	mu     sync.Mutex
+	stopCh chan struct{}
+	once   sync.Once
+
+	// MaxRestarts max restart times in Window. default is 5
+	MaxRestarts int
+	// Window for count the restarts. default is 1 minute
+	Window time.Duration
+	// MinBackoff, MaxBackoff the restart delay. it is doubled on each consecutive crash.
+	//
+	// default is 1s and 30s.
+	MinBackoff, MaxBackoff time.Duration
+	// ResetAfter reset the backoff to MinBackoff if the child has run longer than it. default is Window
+	ResetAfter time.Duration
+	// RestartOnSuccess restart the child even if it exited with code 0.
+	RestartOnSuccess bool
+	// Signals forward to the child. default: SIGINT, SIGTERM, SIGHUP, SIGUSR1, SIGUSR2
+	//
+	// NOTE: on Windows, default is SIGINT, SIGTERM. and cannot send signal to the child,
+	// so the child will be killed on receive them.
+	//
+	// On receive SIGINT or SIGTERM, will stop supervise after the child exited.
+	Signals []os.Signal
+
+	// OnStart hook on the child started
+	OnStart func(c *cmdr.Cmd)
+	// OnExit hook on the child exited, restarts is the number of restarts in the window.
+	OnExit func(c *cmdr.Cmd, err error, restarts int)
+}
+
+// NewSupervisor create a supervisor for the command.
+//
+// NOTE: each start will use a clone of the cmd, the Stdin, Stdout, Stderr are shared.
+// the redirects and Timeout settings of cmdr.Cmd are not used.
+func NewSupervisor(cmd *cmdr.Cmd, fns ...func(s *Supervisor)) *Supervisor {
+	s := &Supervisor{
+		cmd:         cmd,
+		stopCh:      make(chan struct{}),
+		MaxRestarts: 5,
+		Window:      time.Minute,
+		MinBackoff:  time.Second,
+		MaxBackoff:  30 * time.Second,
+		Signals:     defaultSignals,
+	}
+
+	for _, fn := range fns {
+		fn(s)
+	}
+	return s
+}
+
+// PID of the running child. returns 0 if not running.
+func (s *Supervisor) PID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cur != nil && s.cur.Process != nil {
+		return s.cur.Process.Pid
+	}
+	return 0
+}
+
+// Stop supervise, will send SIGTERM to the child and wait it exit.
+func (s *Supervisor) Stop() {
+	s.once.Do(func() { close(s.stopCh) })
+}
+
+// Run and supervise the child command, blocking until:
+//
+//   - the child exited with code 0(if RestartOnSuccess is false)
+//   - stopped by Stop() or received SIGINT, SIGTERM
+//   - too many restarts in the window, will return error
+func (s *Supervisor) Run() error {
+	sigCh := make(chan os.Signal, 1)
+	if len(s.Signals) > 0 {
+		signal.Notify(sigCh, s.Signals...)
+		defer signal.Stop(sigCh)
+	}
+
+	resetAfter := s.ResetAfter
+	if resetAfter <= 0 {
+		resetAfter = s.Window
+	}
+
+	var restarts []time.Time
+	backoff := s.MinBackoff
+	for {
+		startAt := time.Now()
+		c, err := s.start()
+		if err != nil {
+			return err
+		}
+
+		stopped, err := s.wait(c, sigCh)
+		if stopped {
+			return nil
+		}
+
+		// clean the restarts out of the window
+		now := time.Now()
+		for len(restarts) > 0 && now.Sub(restarts[0]) > s.Window {
+			restarts = restarts[1:]
+		}
+
+		if s.OnExit != nil {
+			s.OnExit(c, err, len(restarts))
+		}
+		if err == nil && !s.RestartOnSuccess {
+			return nil
+		}
+
+		if len(restarts) >= s.MaxRestarts {
+			if err == nil {
+				err = errors.New("exited")
+			}
+			return fmt.Errorf("process: too many restarts(%d in %s), last exit: %w", len(restarts), s.Window, err)
+		}
+		restarts = append(restarts, now)
+
+		if now.Sub(startAt) >= resetAfter {
+			backoff = s.MinBackoff
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-s.stopCh:
+			timer.Stop()
+			return nil
+		case sig := <-sigCh:
+			timer.Stop()
+			if isStopSignal(sig) {
+				return nil
+			}
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > s.MaxBackoff {
+			backoff = s.MaxBackoff
+		}
+	}
+}
+
+// start a clone of the command
+func (s *Supervisor) start() (*cmdr.Cmd, error) {
+	c := cloneCmd(s.cmd)
+	if c.BeforeRun != nil {
+		c.BeforeRun(c)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+
+	s.cur = c
+	if s.OnStart != nil {
+		s.OnStart(c)
+	}
+	return c, nil
+}
+
+// wait the child exit, and forward signals to it.
+//
+// On stopping, will kill the child if it not exited after cmdr.DefaultGracePeriod.
+func (s *Supervisor) wait(c *cmdr.Cmd, sigCh chan os.Signal) (stopped bool, err error) {
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- c.Wait()
+	}()
+
+	var killTimer *time.Timer
+	var killCh <-chan time.Time
+	stopping := func() {
+		if killTimer == nil {
+			killTimer = time.NewTimer(cmdr.DefaultGracePeriod)
+			killCh = killTimer.C
+		}
+		stopped = true
+	}
+
+	stopCh := s.stopCh
+	for {
+		select {
+		case err = <-doneCh:
+			if killTimer != nil {
+				killTimer.Stop()
+			}
+
+			s.mu.Lock()
+			s.cur = nil
+			s.mu.Unlock()
+
+			if c.AfterRun != nil {
+				c.AfterRun(c, err)
+			}
+			return stopped, err
+		case sig := <-sigCh:
+			stop := isStopSignal(sig)
+			if stop {
+				stopping()
+			}
+			// send signal is not supported on Windows, kill it directly for stop.
+			if err := c.Process.Signal(sig); err != nil && stop {
+				_ = c.Process.Kill()
+			}
+		case <-stopCh:
+			stopCh = nil // only handle once
+			stopping()
+			if err := c.Process.Signal(syscall.SIGTERM); err != nil {
+				_ = c.Process.Kill()
+			}
+		case <-killCh:
+			_ = c.Process.Kill()
+		}
+	}
+}
+
+func isStopSignal(sig os.Signal) bool {
+	return sig == syscall.SIGINT || sig == syscall.SIGTERM
+}
+
+// clone the command for restart, the exec.Cmd cannot be reused.
+func cloneCmd(c *cmdr.Cmd) *cmdr.Cmd {
+	ec := exec.Command(c.Path)
+	ec.Args = append([]string(nil), c.Args...)
+	ec.Env = c.Env
+	ec.Dir = c.Dir
+	ec.Stdin = c.Stdin
+	ec.Stdout = c.Stdout
+	ec.Stderr = c.Stderr
+	ec.ExtraFiles = c.ExtraFiles
+	ec.SysProcAttr = c.SysProcAttr
+
+	nc := cmdr.WrapGoCmd(ec)
+	nc.Name = c.Name
+	nc.BeforeRun = c.BeforeRun
+	nc.AfterRun = c.AfterRun
+	return nc
+}
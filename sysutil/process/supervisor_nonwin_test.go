@@ -0,0 +1,37 @@
+//go:build !windows
+// +build !windows
+
+package process_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/sysutil/cmdr"
+	"github.com/gookit/goutil/sysutil/process"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestSupervisor_stop(t *testing.T) {
+	// stop by Stop()
+	sv := process.NewSupervisor(cmdr.NewCmd("sleep", "10"))
+	sv.OnStart = func(c *cmdr.Cmd) { sv.Stop() }
+	startAt := time.Now()
+	assert.NoErr(t, sv.Run())
+	assert.True(t, time.Since(startAt) < 3*time.Second)
+
+	// forward signal to the child
+	sv = process.NewSupervisor(cmdr.NewCmd("sh", "-c", "trap 'exit 0' USR1; sleep 10 & wait"))
+	sv.Signals = []os.Signal{syscall.SIGUSR1}
+	sv.OnStart = func(c *cmdr.Cmd) {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+		}()
+	}
+	startAt = time.Now()
+	assert.NoErr(t, sv.Run())
+	assert.True(t, time.Since(startAt) < 3*time.Second)
+}
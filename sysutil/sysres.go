@@ -0,0 +1,97 @@
+package sysutil
+
+import (
+	"errors"
+	"time"
+)
+
+// errResNotSupported error for read system resource info on not supported OS.
+var errResNotSupported = errors.New("sysutil: read system resource info is only supported on Linux")
+
+// MemStat system memory info. unit: bytes. see MemInfo()
+type MemStat struct {
+	Total     uint64
+	Free      uint64
+	Available uint64
+	// Used = Total - Available
+	Used    uint64
+	Buffers uint64
+	Cached  uint64
+
+	SwapTotal uint64
+	SwapFree  uint64
+}
+
+// UsedPercent of the memory
+func (m *MemStat) UsedPercent() float64 { return percentOf(m.Used, m.Total) }
+
+// SwapUsed of the swap memory
+func (m *MemStat) SwapUsed() uint64 { return m.SwapTotal - m.SwapFree }
+
+// CPUStat the CPU info. see CPUInfo()
+type CPUStat struct {
+	// Model name. eg: "Intel(R) Core(TM) i7-8700 CPU @ 3.20GHz"
+	Model string
+	// MHz of the first core
+	MHz float64
+	// Cores logical cores number
+	Cores int
+	// PhysicalCores number. same as Cores if cannot detect.
+	PhysicalCores int
+	// Usage percent of each logical core, sampled over the interval. empty if interval <= 0
+	Usage []float64
+	// TotalUsage percent of all cores, sampled over the interval.
+	TotalUsage float64
+}
+
+// LoadStat the system load average. see LoadAvg()
+type LoadStat struct {
+	Load1, Load5, Load15 float64
+	// Running the number of currently runnable processes(threads)
+	Running int
+	// Total the number of processes(threads) exist on the system
+	Total int
+}
+
+// DiskStat the disk usage of a filesystem. unit: bytes. see DiskUsage()
+type DiskStat struct {
+	Path  string
+	Total uint64
+	Free  uint64
+	// Available for unprivileged user
+	Available uint64
+	Used      uint64
+
+	InodesTotal uint64
+	InodesFree  uint64
+}
+
+// UsedPercent of the disk. like the "Use%" of the `df` command.
+func (d *DiskStat) UsedPercent() float64 { return percentOf(d.Used, d.Used+d.Available) }
+
+// MountPoint info. see MountPoints()
+type MountPoint struct {
+	// Device name. eg: /dev/sda1, tmpfs
+	Device string
+	// Path the mount point path
+	Path string
+	// FSType filesystem type. eg: ext4, tmpfs
+	FSType  string
+	Options []string
+}
+
+// UptimeString get the system uptime as string. eg: "3h25m10s"
+func UptimeString() string {
+	d, err := Uptime()
+	if err != nil {
+		return ""
+	}
+	return d.Truncate(time.Second).String()
+}
+
+func percentOf(val, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(val) * 100 / float64(total)
+}
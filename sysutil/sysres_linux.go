@@ -0,0 +1,329 @@
+package sysutil
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// MemInfo get the system memory info by read /proc/meminfo
+//
+// Usage:
+//
+//	mem, err := sysutil.MemInfo()
+//	fmt.Printf("memory used: %.1f%%\n", mem.UsedPercent())
+func MemInfo() (*MemStat, error) {
+	mp, err := readKVFile("/proc/meminfo", ":")
+	if err != nil {
+		return nil, err
+	}
+
+	// the value unit is kB. eg: "16318496 kB"
+	kb := func(name string) uint64 {
+		val, _ := strconv.ParseUint(strings.TrimSuffix(mp[name], " kB"), 10, 64)
+		return val * 1024
+	}
+
+	m := &MemStat{
+		Total:     kb("MemTotal"),
+		Free:      kb("MemFree"),
+		Buffers:   kb("Buffers"),
+		Cached:    kb("Cached") + kb("SReclaimable"),
+		SwapTotal: kb("SwapTotal"),
+		SwapFree:  kb("SwapFree"),
+	}
+
+	if _, ok := mp["MemAvailable"]; ok {
+		m.Available = kb("MemAvailable")
+	} else { // kernel < 3.14
+		m.Available = m.Free + m.Buffers + m.Cached
+	}
+
+	if m.Total > m.Available {
+		m.Used = m.Total - m.Available
+	}
+	return m, nil
+}
+
+// CPUInfo get the CPU info by read /proc/cpuinfo.
+//
+// If interval > 0, will sample the CPU usage over the interval by read /proc/stat.
+//
+// Usage:
+//
+//	cpu, err := sysutil.CPUInfo(time.Second)
+//	fmt.Println(cpu.Model, cpu.Cores, cpu.TotalUsage, cpu.Usage)
+func CPUInfo(interval time.Duration) (*CPUStat, error) {
+	bs, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &CPUStat{}
+	physIDs := make(map[string]bool)
+	var physID string
+
+	s := bufio.NewScanner(bytes.NewReader(bs))
+	for s.Scan() {
+		key, val, ok := strings.Cut(s.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		switch key {
+		case "processor":
+			cs.Cores++
+		case "model name", "Model", "cpu model":
+			if cs.Model == "" {
+				cs.Model = val
+			}
+		case "cpu MHz":
+			if cs.MHz == 0 {
+				cs.MHz, _ = strconv.ParseFloat(val, 64)
+			}
+		case "physical id":
+			physID = val
+		case "core id":
+			physIDs[physID+"/"+val] = true
+		}
+	}
+
+	cs.PhysicalCores = len(physIDs)
+	if cs.PhysicalCores == 0 {
+		cs.PhysicalCores = cs.Cores
+	}
+
+	if interval > 0 {
+		if err = sampleCPUUsage(cs, interval); err != nil {
+			return nil, err
+		}
+	}
+	return cs, nil
+}
+
+// cpu times in /proc/stat. index 0 is the total of all cores.
+type cpuTimes struct {
+	idle, total uint64
+}
+
+func readCPUTimes() ([]cpuTimes, error) {
+	bs, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return nil, err
+	}
+
+	var list []cpuTimes
+	for _, line := range strings.Split(string(bs), "\n") {
+		if !strings.HasPrefix(line, "cpu") {
+			continue
+		}
+
+		// cpu user nice system idle iowait irq softirq steal guest guest_nice
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+
+		var ct cpuTimes
+		// the guest times are included in user and nice, so only sum the first 8.
+		for i := 1; i < len(fields) && i <= 8; i++ {
+			val, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			ct.total += val
+			// idle + iowait
+			if i == 4 || i == 5 {
+				ct.idle += val
+			}
+		}
+		list = append(list, ct)
+	}
+
+	if len(list) == 0 {
+		return nil, errors.New("not found cpu times in /proc/stat")
+	}
+	return list, nil
+}
+
+func sampleCPUUsage(cs *CPUStat, interval time.Duration) error {
+	before, err := readCPUTimes()
+	if err != nil {
+		return err
+	}
+
+	time.Sleep(interval)
+	after, err := readCPUTimes()
+	if err != nil {
+		return err
+	}
+
+	if len(after) != len(before) {
+		return errors.New("the CPU cores changed on sampling usage")
+	}
+
+	cs.Usage = make([]float64, 0, len(after)-1)
+	for i := range after {
+		var usage float64
+		if total := tickDelta(before[i].total, after[i].total); total > 0 {
+			// the idle ticks may be greater than total on the counters are reset
+			if idle := tickDelta(before[i].idle, after[i].idle); idle < total {
+				usage = percentOf(total-idle, total)
+			}
+		}
+
+		if i == 0 {
+			cs.TotalUsage = usage
+		} else {
+			cs.Usage = append(cs.Usage, usage)
+		}
+	}
+	return nil
+}
+
+// the delta of the CPU ticks. returns 0 on the ticks go backwards(eg: CPU hotplug), avoid the unsigned underflow.
+func tickDelta(before, after uint64) uint64 {
+	if after < before {
+		return 0
+	}
+	return after - before
+}
+
+// LoadAvg get the system load average by read /proc/loadavg
+func LoadAvg() (*LoadStat, error) {
+	bs, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return nil, err
+	}
+
+	// eg: "0.52 0.58 0.59 2/1234 56789"
+	fields := strings.Fields(string(bs))
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid /proc/loadavg contents: %s", bs)
+	}
+
+	ls := &LoadStat{}
+	for i, ptr := range []*float64{&ls.Load1, &ls.Load5, &ls.Load15} {
+		if *ptr, err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return nil, err
+		}
+	}
+
+	running, total, _ := strings.Cut(fields[3], "/")
+	ls.Running, _ = strconv.Atoi(running)
+	ls.Total, _ = strconv.Atoi(total)
+	return ls, nil
+}
+
+// Uptime get the system uptime by read /proc/uptime
+func Uptime() (time.Duration, error) {
+	bs, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, err
+	}
+
+	// eg: "350735.47 234388.90"
+	fields := strings.Fields(string(bs))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid /proc/uptime contents: %s", bs)
+	}
+
+	sec, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(sec * float64(time.Second)), nil
+}
+
+// DiskUsage get the disk usage of the filesystem that path on. by statfs
+//
+// Usage:
+//
+//	du, err := sysutil.DiskUsage("/")
+//	fmt.Printf("disk used: %.1f%%\n", du.UsedPercent())
+func DiskUsage(path string) (*DiskStat, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return nil, err
+	}
+
+	bsize := uint64(st.Bsize)
+	return &DiskStat{
+		Path:        path,
+		Total:       st.Blocks * bsize,
+		Free:        st.Bfree * bsize,
+		Available:   st.Bavail * bsize,
+		Used:        (st.Blocks - st.Bfree) * bsize,
+		InodesTotal: st.Files,
+		InodesFree:  st.Ffree,
+	}, nil
+}
+
+// MountPoints get all mount points by read /proc/self/mounts
+func MountPoints() ([]*MountPoint, error) {
+	bs, err := os.ReadFile("/proc/self/mounts")
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*MountPoint
+	for _, line := range strings.Split(string(bs), "\n") {
+		// eg: "/dev/sda1 / ext4 rw,relatime 0 0"
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		list = append(list, &MountPoint{
+			Device:  unescapeMountField(fields[0]),
+			Path:    unescapeMountField(fields[1]),
+			FSType:  fields[2],
+			Options: strings.Split(fields[3], ","),
+		})
+	}
+	return list, nil
+}
+
+// the space, tab, newline and backslash are escaped as octal in mounts. eg: "\040" => " "
+func unescapeMountField(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// read the "key: value" lines file to map
+func readKVFile(fPath, sep string) (map[string]string, error) {
+	bs, err := os.ReadFile(fPath)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := make(map[string]string)
+	for _, line := range strings.Split(string(bs), "\n") {
+		if key, val, ok := strings.Cut(line, sep); ok {
+			mp[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return mp, nil
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package sysutil
+
+import "time"
+
+// MemInfo get the system memory info. only supported on Linux.
+func MemInfo() (*MemStat, error) { return nil, errResNotSupported }
+
+// CPUInfo get the CPU info. only supported on Linux.
+func CPUInfo(_ time.Duration) (*CPUStat, error) { return nil, errResNotSupported }
+
+// LoadAvg get the system load average. only supported on Linux.
+func LoadAvg() (*LoadStat, error) { return nil, errResNotSupported }
+
+// Uptime get the system uptime. only supported on Linux.
+func Uptime() (time.Duration, error) { return 0, errResNotSupported }
+
+// DiskUsage get the disk usage of the filesystem. only supported on Linux.
+func DiskUsage(_ string) (*DiskStat, error) { return nil, errResNotSupported }
+
+// MountPoints get all mount points. only supported on Linux.
+func MountPoints() ([]*MountPoint, error) { return nil, errResNotSupported }
@@ -0,0 +1,69 @@
+package sysutil_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/sysutil"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestSysResources(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		_, err := sysutil.MemInfo()
+		assert.ErrSubMsg(t, err, "only supported on Linux")
+		return
+	}
+
+	mem, err := sysutil.MemInfo()
+	assert.NoErr(t, err)
+	assert.True(t, mem.Total > 0)
+	assert.True(t, mem.Available <= mem.Total)
+	assert.Eq(t, mem.Total-mem.Available, mem.Used)
+	assert.True(t, mem.UsedPercent() > 0 && mem.UsedPercent() <= 100)
+
+	cpu, err := sysutil.CPUInfo(0)
+	assert.NoErr(t, err)
+	assert.True(t, cpu.Cores > 0)
+	assert.True(t, cpu.PhysicalCores > 0 && cpu.PhysicalCores <= cpu.Cores)
+	assert.Empty(t, cpu.Usage)
+
+	cpu, err = sysutil.CPUInfo(50 * time.Millisecond)
+	assert.NoErr(t, err)
+	assert.NotEmpty(t, cpu.Usage)
+	assert.True(t, cpu.TotalUsage >= 0 && cpu.TotalUsage <= 100)
+
+	load, err := sysutil.LoadAvg()
+	assert.NoErr(t, err)
+	assert.True(t, load.Load1 >= 0)
+	assert.True(t, load.Total > 0)
+
+	up, err := sysutil.Uptime()
+	assert.NoErr(t, err)
+	assert.True(t, up > 0)
+	assert.NotEmpty(t, sysutil.UptimeString())
+
+	du, err := sysutil.DiskUsage(t.TempDir())
+	assert.NoErr(t, err)
+	assert.True(t, du.Total > 0)
+	assert.True(t, du.Free <= du.Total)
+	assert.True(t, du.UsedPercent() >= 0 && du.UsedPercent() <= 100)
+
+	_, err = sysutil.DiskUsage("/path/not-exists")
+	assert.Err(t, err)
+
+	mps, err := sysutil.MountPoints()
+	assert.NoErr(t, err)
+	assert.NotEmpty(t, mps)
+
+	var hasRoot bool
+	for _, mp := range mps {
+		if mp.Path == "/" {
+			hasRoot = true
+			assert.NotEmpty(t, mp.FSType)
+			assert.NotEmpty(t, mp.Options)
+		}
+	}
+	assert.True(t, hasRoot)
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
 	"hash/crc32"
@@ -93,33 +94,42 @@ func HashSum(algo string, src any) []byte {
 	return hh.Sum(nil)
 }
 
-// NewHash create hash.Hash instance
+// NewHash create hash.Hash instance, will panic on the algo is invalid. see TryNewHash()
 //
 // algo: crc32, crc64, md5, sha1, sha224, sha256, sha384, sha512, sha512_224, sha512_256
 func NewHash(algo string) hash.Hash {
+	h, err := TryNewHash(algo)
+	if err != nil {
+		panic(err.Error())
+	}
+	return h
+}
+
+// TryNewHash create hash.Hash instance, returns error on the algo is invalid.
+func TryNewHash(algo string) (hash.Hash, error) {
 	switch strings.ToLower(algo) {
 	case AlgoCRC32:
-		return crc32.NewIEEE()
+		return crc32.NewIEEE(), nil
 	case AlgoCRC64:
-		return crc64.New(crc64.MakeTable(crc64.ISO))
+		return crc64.New(crc64.MakeTable(crc64.ISO)), nil
 	case AlgoMD5:
-		return md5.New()
+		return md5.New(), nil
 	case AlgoSHA1:
-		return sha1.New()
+		return sha1.New(), nil
 	case AlgoSHA224:
-		return sha256.New224()
+		return sha256.New224(), nil
 	case AlgoSHA256:
-		return sha256.New()
+		return sha256.New(), nil
 	case AlgoSHA384:
-		return sha512.New384()
+		return sha512.New384(), nil
 	case AlgoSHA512:
-		return sha512.New()
+		return sha512.New(), nil
 	case "sha512_224":
-		return sha512.New512_224()
+		return sha512.New512_224(), nil
 	case "sha512_256":
-		return sha512.New512_256()
+		return sha512.New512_256(), nil
 	default:
-		panic("invalid hash algorithm:" + algo)
+		return nil, errors.New("invalid hash algorithm:" + algo)
 	}
 }
 
@@ -26,6 +26,12 @@ func TestHash(t *testing.T) {
 	assert.Panics(t, func() {
 		hashutil.Hash("unknown", nil)
 	})
+
+	h, err := hashutil.TryNewHash("SHA256")
+	assert.NoErr(t, err)
+	assert.Eq(t, 32, h.Size())
+	_, err = hashutil.TryNewHash("unknown")
+	assert.ErrMsg(t, err, "invalid hash algorithm:unknown")
 }
 
 func TestHash32(t *testing.T) {
@@ -2,9 +2,11 @@ package finder
 
 import (
 	"fmt"
+	"io/fs"
 	"strings"
 
 	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/fsutil"
 	"github.com/gookit/goutil/strutil"
 )
 
@@ -271,6 +273,66 @@ func (c *Config) Init() *Config {
 	return c
 }
 
+// FilterFunc create a fsutil.FilterFunc by the config rules.
+// can be used for fsutil.FindInDir, fsutil.CopyDir and more.
+//
+// NOTE: include matchers only apply to files, the dirs only check exclude matchers.
+func (c *Config) FilterFunc() fsutil.FilterFunc {
+	c.Init()
+	return func(fPath string, ent fs.DirEntry) bool {
+		excluded, matched := c.matchElem(NewElem(fPath, ent))
+		if ent.IsDir() {
+			return !excluded
+		}
+		return !excluded && matched
+	}
+}
+
+// matchElem check the elem by the config rules. used by Finder and FilterFunc()
+//
+//   - excluded: the elem is excluded by the dot and exclude matchers. the excluded dir will not be found in.
+//   - matched: the elem is matched by the include matchers.
+func (c *Config) matchElem(el Elem) (excluded, matched bool) {
+	isDir := el.IsDir()
+	if name := el.Name(); name[0] == '.' {
+		if isDir {
+			if c.ExcludeDotDir {
+				return true, false
+			}
+		} else if c.ExcludeDotFile {
+			return true, false
+		}
+	}
+
+	// apply generic filters
+	if !applyExMatchers(el, c.ExMatchers) {
+		return true, false
+	}
+
+	if isDir {
+		if !applyExMatchers(el, c.DirExMatchers) {
+			return true, false
+		}
+		return false, c.applyIncludes(el, c.DirMatchers)
+	}
+
+	if !applyExMatchers(el, c.FileExMatchers) {
+		return true, false
+	}
+	return false, c.applyIncludes(el, c.FileMatchers)
+}
+
+// apply the generic include matchers, then the dir or file matchers.
+func (c *Config) applyIncludes(el Elem, typMatchers []Matcher) bool {
+	if len(c.Matchers) > 0 {
+		if applyMatchers(el, c.Matchers) {
+			return true
+		}
+		return len(typMatchers) > 0 && applyMatchers(el, typMatchers)
+	}
+	return applyMatchers(el, typMatchers)
+}
+
 //
 // --------- config finder by rules ---------
 //
@@ -314,6 +376,9 @@ func (f *Finder) WithRules(addOrExclude bool, rules []string) *Finder {
 	return f
 }
 
+// FilterFunc create a fsutil.FilterFunc by the finder config. see Config.FilterFunc()
+func (f *Finder) FilterFunc() fsutil.FilterFunc { return f.c.FilterFunc() }
+
 //
 // --------- config for finder ---------
 //
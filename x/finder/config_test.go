@@ -3,6 +3,7 @@ package finder_test
 import (
 	"testing"
 
+	"github.com/gookit/goutil/testutil"
 	"github.com/gookit/goutil/x/assert"
 	"github.com/gookit/goutil/x/finder"
 )
@@ -183,3 +184,23 @@ func TestConfig_LoadRules(t *testing.T) {
 		assert.Len(t, c.FileExMatchers, 0)
 	})
 }
+
+func TestConfig_FilterFunc(t *testing.T) {
+	c := finder.NewConfig()
+	c.IncludeExts = []string{".go"}
+	c.ExcludeDirs = []string{"vendor"}
+	c.ExcludeFiles = []string{"*_test.go"}
+
+	fn := c.FilterFunc()
+	assert.True(t, fn("src/main.go", testutil.NewDirEnt("src/main.go")))
+	assert.False(t, fn("src/main_test.go", testutil.NewDirEnt("src/main_test.go")))
+	assert.False(t, fn("src/README.md", testutil.NewDirEnt("src/README.md")))
+	assert.True(t, fn("src/sub", testutil.NewDirEnt("src/sub", true)))
+	assert.False(t, fn("src/vendor", testutil.NewDirEnt("src/vendor", true)))
+	assert.False(t, fn("src/.git", testutil.NewDirEnt("src/.git", true)))
+
+	ff := finder.NewFinder().IncludeName("*.md")
+	fn = ff.FilterFunc()
+	assert.True(t, fn("src/README.md", testutil.NewDirEnt("src/README.md")))
+	assert.False(t, fn("src/main.go", testutil.NewDirEnt("src/main.go")))
+}
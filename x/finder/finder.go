@@ -268,55 +268,24 @@ func (f *Finder) findDir(dirPath string, depth int) {
 
 	cfg := f.c
 	depth++
-	var ok bool
 
 	for _, ent := range deList {
-		name := ent.Name()
-		isDir := ent.IsDir()
-		if name[0] == '.' {
-			if isDir {
-				if cfg.ExcludeDotDir {
-					continue
-				}
-			} else if cfg.ExcludeDotFile {
-				continue
-			}
-		}
-
-		fullPath := filepath.Join(dirPath, name)
+		fullPath := filepath.Join(dirPath, ent.Name())
 		el := NewElem(fullPath, ent)
 
-		// apply generic filters
-		if !applyExMatchers(el, cfg.ExMatchers) {
+		excluded, ok := cfg.matchElem(el)
+		if excluded {
 			continue
 		}
 
-		// --- dir: apply dir filters
-		if isDir {
-			if !applyExMatchers(el, cfg.DirExMatchers) {
-				continue
-			}
-
-			if len(cfg.Matchers) > 0 {
-				ok = applyMatchers(el, cfg.Matchers)
-				if !ok && len(cfg.DirMatchers) > 0 {
-					ok = applyMatchers(el, cfg.DirMatchers)
-				}
-			} else {
-				ok = applyMatchers(el, cfg.DirMatchers)
-			}
-
-			// match ok, send to consumer
+		// --- dir: send to consumer on matched, and find in sub dir
+		if el.IsDir() {
 			if ok && cfg.FindFlags&FlagDir > 0 {
 				if cfg.CacheResult {
 					f.caches = append(f.caches, el)
 				}
 				f.ch <- el
 				atomic.AddUint32(&f.num, 1)
-
-				// if cfg.FindFlags == FlagDir {
-				// 	continue // only find sub-dir on ok=false
-				// }
 			}
 
 			// find in sub dir. 添加子目录任务
@@ -331,26 +300,7 @@ func (f *Finder) findDir(dirPath string, depth int) {
 			continue
 		}
 
-		// --- type: file
-		if cfg.FindFlags&FlagFile == 0 {
-			continue
-		}
-
-		// apply file filters
-		if !applyExMatchers(el, cfg.FileExMatchers) {
-			continue
-		}
-
-		if len(cfg.Matchers) > 0 {
-			ok = applyMatchers(el, cfg.Matchers)
-			if !ok && len(cfg.FileMatchers) > 0 {
-				ok = applyMatchers(el, cfg.FileMatchers)
-			}
-		} else {
-			ok = applyMatchers(el, cfg.FileMatchers)
-		}
-
-		// write to consumer
+		// --- type: file. write to consumer
 		if ok && cfg.FindFlags&FlagFile > 0 {
 			if cfg.CacheResult {
 				f.caches = append(f.caches, el)